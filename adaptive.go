@@ -0,0 +1,59 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// AdaptiveBlock sizes the blocks reserved by block allocators (NewAdaptiveSegment,
+// sqlid.NewAdaptiveHiLo) from the recent consumption rate, so that each block lasts about
+// target: the size doubles when a block is used up in less than half of target and halves
+// when it lasts more than twice target, always between min and max. This bounds the
+// backend load to about one reservation per target under growing load, and the IDs lost
+// when the process exits to the consumption of about twice target (at most max) under
+// shrinking load. Safe for concurrent use, but each allocator needs its own.
+type AdaptiveBlock struct {
+	min, max int64
+	target   time.Duration
+	// now returns the monotonic clock, replaced in tests.
+	now func() time.Time
+
+	mu   sync.Mutex
+	size int64
+	last time.Time
+}
+
+// NewAdaptiveBlock returns an AdaptiveBlock starting with blocks of min IDs.
+func NewAdaptiveBlock(min, max int64, target time.Duration) (*AdaptiveBlock, error) {
+	if min < 1 || max < min || target <= 0 {
+		return nil, fmt.Errorf("NewAdaptiveBlock(%d, %d, %v): sizes must be positive, min<=max, target positive",
+			min, max, target)
+	}
+	return &AdaptiveBlock{min: min, max: max, target: target, now: time.Now, size: min}, nil
+}
+
+// Next records a reservation and returns the size of the block to reserve, adjusted to
+// the time elapsed since the previous one.
+func (a *AdaptiveBlock) Next() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := a.now()
+	if !a.last.IsZero() {
+		switch elapsed := now.Sub(a.last); {
+		case elapsed < a.target/2:
+			a.size = min(a.size*2, a.max)
+		case elapsed > a.target*2:
+			a.size = max(a.size/2, a.min)
+		}
+	}
+	a.last = now
+	return a.size
+}
+
+// Size returns the size of the last block, for metrics.
+func (a *AdaptiveBlock) Size() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.size
+}
@@ -0,0 +1,92 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdaptiveBlock(t *testing.T) {
+	t.Parallel()
+	for _, args := range [][2]int64{{0, 10}, {10, 5}} {
+		if _, err := NewAdaptiveBlock(args[0], args[1], time.Second); err == nil {
+			t.Errorf("TestAdaptiveBlock %v: expected error", args)
+		}
+	}
+	a, err := NewAdaptiveBlock(10, 50, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Unix(1e9, 0)
+	a.now = func() time.Time { return now }
+	var tests = []struct {
+		elapsed  time.Duration
+		expected int64
+	}{
+		{0, 10}, // First reservation.
+		{time.Second, 20},
+		{time.Second, 40},
+		{time.Second, 50}, // Capped.
+		{time.Minute, 50}, // On target.
+		{time.Hour, 25},
+		{time.Hour, 12},
+		{time.Hour, 10}, // Floored.
+	}
+	for i, test := range tests {
+		now = now.Add(test.elapsed)
+		if v := a.Next(); v != test.expected || a.Size() != test.expected {
+			t.Errorf("TestAdaptiveBlock %d: got %v, expected %v", i, v, test.expected)
+		}
+	}
+}
+
+func TestAdaptiveSegment(t *testing.T) {
+	t.Parallel()
+	a, _ := NewAdaptiveBlock(2, 8, time.Minute)
+	now := time.Unix(1e9, 0)
+	a.now = func() time.Time { return now }
+	store := &segmentStore{}
+	gen, err := NewAdaptiveSegment(store, a, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Segments of 2, 4 and 8 IDs, the next one reserved when the previous one is used up.
+	for i := int64(1); i <= 14; i++ {
+		if v, err := gen.NewIDs(1); err != nil || v != i {
+			t.Fatalf("TestAdaptiveSegment %d: got %v (error %v)", i, v, err)
+		}
+	}
+	if _, err := gen.NewIDs(9); err == nil {
+		t.Error("TestAdaptiveSegment: expected error for n > max")
+	}
+	gen.(interface{ Close() error }).Close()
+	if store.calls != 4 || a.Size() != 8 {
+		t.Errorf("TestAdaptiveSegment: got %d calls, size %d", store.calls, a.Size())
+	}
+}
+
+func TestAdaptiveSegmentLargeRequest(t *testing.T) {
+	t.Parallel()
+	a, _ := NewAdaptiveBlock(10, 100, time.Hour)
+	store := &segmentStore{}
+	gen, _ := NewAdaptiveSegment(store, a, 0.5)
+	defer gen.(interface{ Close() error }).Close()
+	// The segment prefetched after the first request is too small for the second one.
+	seen := map[int64]bool{}
+	for _, n := range []int64{8, 50, 50} {
+		last, err := gen.NewIDs(n)
+		if err != nil {
+			t.Fatalf("TestAdaptiveSegmentLargeRequest %d: got error %v", n, err)
+		}
+		for id := last - n + 1; id <= last; id++ {
+			if seen[id] {
+				t.Errorf("TestAdaptiveSegmentLargeRequest %d: got %v twice", n, id)
+			}
+			seen[id] = true
+		}
+		store.Lock()
+		if last > store.value {
+			t.Errorf("TestAdaptiveSegmentLargeRequest %d: got %v past reserved %v", n, last, store.value)
+		}
+		store.Unlock()
+	}
+}
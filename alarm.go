@@ -0,0 +1,142 @@
+package idgen
+
+import (
+	"sync"
+	"time"
+)
+
+// ClockEventKind classifies suspicious clock behavior reported to ClockAlarm.OnEvent.
+type ClockEventKind int
+
+// Kinds of ClockEvent.
+const (
+	// ClockRegression means the clock went back.
+	ClockRegression ClockEventKind = iota
+	// ClockJump means the clock moved forward faster than real time.
+	ClockJump
+	// ClockFrozen means the clock stopped.
+	ClockFrozen
+)
+
+func (k ClockEventKind) String() string {
+	switch k {
+	case ClockRegression:
+		return "regression"
+	case ClockJump:
+		return "jump"
+	case ClockFrozen:
+		return "frozen"
+	}
+	return "unknown"
+}
+
+// ClockEvent describes suspicious clock behavior.
+type ClockEvent struct {
+	Kind ClockEventKind
+	// Now is the clock reading that triggered the event.
+	Now time.Time
+	// Delta is how far the clock went back (ClockRegression), how much it moved beyond
+	// the real time elapsed (ClockJump) or for how long it was stopped (ClockFrozen).
+	Delta time.Duration
+}
+
+// ClockAlarm configures WithClockAlarm.
+type ClockAlarm struct {
+	// MaxJump is the largest tolerated difference between the clock's progress and the
+	// real time elapsed (measured with the monotonic clock) between readings, one Second
+	// if zero.
+	MaxJump time.Duration
+	// MaxFrozen is how long the clock may return the same reading, one Second if zero.
+	MaxFrozen time.Duration
+	// OnEvent is called on every regression and jump, and once per freeze. It runs while
+	// the generator is locked, so it must be fast (e.g. hand the event to a channel) and
+	// must not call the generator.
+	OnEvent func(ClockEvent)
+}
+
+// WithClockAlarm makes the generator report suspicious clock behavior to a.OnEvent, so
+// operators can be paged before IDs fail (or, with WithWaitOnClockRollback or
+// WithClockTolerance, silently stall). Detection relies on the clock being read, so
+// nothing is reported while no IDs are generated. A MonotonicClock (like the default
+// SystemClock) cannot misbehave, so with one the wall clock is watched instead: events
+// then report wall clock steps that the IDs' timestamps do not follow.
+func WithClockAlarm(a ClockAlarm) Option {
+	return func(o *options) {
+		if a.MaxJump <= 0 {
+			a.MaxJump = time.Second
+		}
+		if a.MaxFrozen <= 0 {
+			a.MaxFrozen = time.Second
+		}
+		o.alarm = &a
+	}
+}
+
+// alarmClock wraps a Clock, comparing its readings with the monotonic clock. For a
+// MonotonicClock, those of watched (the wall clock) are compared instead.
+type alarmClock struct {
+	clock   Clock
+	watched Clock
+	alarm   ClockAlarm
+	// real returns the monotonic clock, replaced in tests.
+	real func() time.Time
+
+	mu       sync.Mutex
+	started  bool
+	last     int64
+	lastReal time.Time
+	// changed is when the clock last moved, and frozen whether it was reported stopped.
+	changed time.Time
+	frozen  bool
+}
+
+func newAlarmClock(c Clock, a ClockAlarm) *alarmClock {
+	var watched Clock
+	if _, ok := c.(*MonotonicClock); ok {
+		watched = WallClock
+	}
+	return &alarmClock{clock: c, watched: watched, alarm: a, real: time.Now}
+}
+
+func (a *alarmClock) Now() int64 {
+	if a.watched != nil {
+		a.check(a.watched.Now())
+		return a.clock.Now()
+	}
+	now := a.clock.Now()
+	a.check(now)
+	return now
+}
+
+func (a *alarmClock) check(now int64) {
+	real := a.real()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.started {
+		a.started = true
+		a.last, a.lastReal, a.changed = now, real, real
+		return
+	}
+	elapsed := real.Sub(a.lastReal)
+	switch delta := time.Duration(now - a.last); {
+	case delta < 0:
+		a.report(ClockRegression, now, -delta)
+	case delta-elapsed > a.alarm.MaxJump:
+		a.report(ClockJump, now, delta-elapsed)
+	case delta == 0:
+		if stopped := real.Sub(a.changed); stopped > a.alarm.MaxFrozen && !a.frozen {
+			a.frozen = true
+			a.report(ClockFrozen, now, stopped)
+		}
+	}
+	if now != a.last {
+		a.changed, a.frozen = real, false
+	}
+	a.last, a.lastReal = now, real
+}
+
+func (a *alarmClock) report(kind ClockEventKind, now int64, delta time.Duration) {
+	if a.alarm.OnEvent != nil {
+		a.alarm.OnEvent(ClockEvent{Kind: kind, Now: time.Unix(0, now), Delta: delta})
+	}
+}
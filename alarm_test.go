@@ -0,0 +1,89 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockAlarm(t *testing.T) {
+	t.Parallel()
+	var events []ClockEvent
+	var wall, real time.Duration
+	a := newAlarmClock(ClockFunc(func() int64 { return int64(wall) }), ClockAlarm{
+		MaxJump:   time.Second,
+		MaxFrozen: 2 * time.Second,
+		OnEvent:   func(e ClockEvent) { events = append(events, e) },
+	})
+	start := time.Now()
+	a.real = func() time.Time { return start.Add(real) }
+
+	var tests = []struct {
+		wall, real time.Duration
+		events     []ClockEvent
+	}{
+		{time.Hour, 0, nil},
+		{time.Hour + time.Second, time.Second, nil},
+		// Idle for a while.
+		{2 * time.Hour, time.Hour, nil},
+		{2*time.Hour - time.Millisecond, time.Hour, []ClockEvent{
+			{ClockRegression, time.Unix(0, int64(2*time.Hour-time.Millisecond)), time.Millisecond}}},
+		{3 * time.Hour, time.Hour + time.Second, []ClockEvent{
+			{ClockJump, time.Unix(0, int64(3*time.Hour)), time.Hour - time.Second + time.Millisecond}}},
+		{3 * time.Hour, time.Hour + 2*time.Second, nil},
+		{3 * time.Hour, time.Hour + 4*time.Second, []ClockEvent{
+			{ClockFrozen, time.Unix(0, int64(3*time.Hour)), 3 * time.Second}}},
+		// Reported once per freeze.
+		{3 * time.Hour, time.Hour + 10*time.Second, nil},
+		{3*time.Hour + time.Millisecond, time.Hour + 10*time.Second, nil},
+	}
+	for i, test := range tests {
+		events = nil
+		wall, real = test.wall, test.real
+		if now := a.Now(); now != int64(wall) {
+			t.Errorf("TestClockAlarm %d: got %v, expected %v", i, now, int64(wall))
+		}
+		if len(events) != len(test.events) || (len(events) == 1 && (events[0].Kind != test.events[0].Kind ||
+			!events[0].Now.Equal(test.events[0].Now) || events[0].Delta != test.events[0].Delta)) {
+			t.Errorf("TestClockAlarm %d: got events %v, expected %v", i, events, test.events)
+		}
+	}
+}
+
+func TestSnowflakeClockAlarm(t *testing.T) {
+	t.Parallel()
+	var kinds []ClockEventKind
+	now := int64(10 * time.Millisecond)
+	gen := NewSnowflake(1, WithClock(ClockFunc(func() int64 { return now })),
+		WithClockAlarm(ClockAlarm{OnEvent: func(e ClockEvent) { kinds = append(kinds, e.Kind) }}))
+	gen.NewIDs(1)
+	now -= int64(5 * time.Millisecond)
+	if _, err := gen.NewIDs(1); err != ErrClockMovedBack {
+		t.Errorf("TestSnowflakeClockAlarm: got error %v, expected %v", err, ErrClockMovedBack)
+	}
+	if len(kinds) != 1 || kinds[0] != ClockRegression || kinds[0].String() != "regression" {
+		t.Errorf("TestSnowflakeClockAlarm: got %v, expected [regression]", kinds)
+	}
+}
+
+func TestClockAlarmDefault(t *testing.T) {
+	t.Parallel()
+	var kinds []ClockEventKind
+	o := newOptions([]Option{WithClockAlarm(ClockAlarm{
+		OnEvent: func(e ClockEvent) { kinds = append(kinds, e.Kind) },
+	})})
+	a, ok := o.clock.(*alarmClock)
+	if !ok || a.clock != SystemClock || a.watched != WallClock {
+		t.Fatalf("TestClockAlarmDefault: got clock %#v, expected SystemClock watching WallClock", o.clock)
+	}
+	// The wall clock is set back an hour: IDs keep following the monotonic clock.
+	wall := time.Now().UnixNano()
+	a.watched = ClockFunc(func() int64 { return wall })
+	a.Now()
+	wall -= int64(time.Hour)
+	if now := a.Now(); now < time.Now().UnixNano()-int64(time.Minute) {
+		t.Errorf("TestClockAlarmDefault: got %v, expected the monotonic clock", time.Unix(0, now))
+	}
+	if len(kinds) != 1 || kinds[0] != ClockRegression {
+		t.Errorf("TestClockAlarmDefault: got %v, expected [regression]", kinds)
+	}
+}
@@ -0,0 +1,43 @@
+package idgen
+
+import "fmt"
+
+// base58 is Bitcoin's alphabet: alphanumerics without 0, O, I and l, which are easily
+// confused.
+const base58 = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// EncodeBase58 returns v in base58 (Bitcoin alphabet), without leading zeros: at most 11
+// characters, against 19 decimal digits. Negative values are encoded as their 64-bit
+// two's complement.
+func EncodeBase58(v int64) string {
+	return encodeBase(uint64(v), base58)
+}
+
+// DecodeBase58 decodes a value encoded by EncodeBase58.
+func DecodeBase58(s string) (int64, error) {
+	u, err := decodeBase(s, base58)
+	if err != nil {
+		return 0, fmt.Errorf("DecodeBase58(%q): %v", s, err)
+	}
+	return int64(u), nil
+}
+
+// Base58 wraps an ID generator, formatting IDs with EncodeBase58. Safe for concurrent
+// use if gen is.
+type Base58 struct {
+	gen Interface
+}
+
+// NewBase58 returns a Base58 generator around gen.
+func NewBase58(gen Interface) *Base58 {
+	return &Base58{gen}
+}
+
+// NewID generates an ID and returns its base58 form.
+func (b *Base58) NewID() (string, error) {
+	id, err := b.gen.NewIDs(1)
+	if err != nil {
+		return "", err
+	}
+	return EncodeBase58(id), nil
+}
@@ -0,0 +1,38 @@
+package idgen
+
+import "testing"
+
+func TestBase58(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		v int64
+		s string
+	}{
+		{0, "1"},
+		{57, "z"},
+		{58, "21"},
+		{1<<63 - 1, "NQm6nKp8qFC"},
+		{-1, "jpXCZedGfVQ"},
+	}
+	for i, test := range tests {
+		if s := EncodeBase58(test.v); s != test.s {
+			t.Errorf("TestBase58 %d: got %q, expected %q", i, s, test.s)
+		}
+		if v, err := DecodeBase58(test.s); err != nil || v != test.v {
+			t.Errorf("TestBase58 %d: got %v (error %v), expected %v", i, v, err, test.v)
+		}
+	}
+	for _, s := range []string{"", "0", "Il", "jpXCZedGfVR"} {
+		if v, err := DecodeBase58(s); err == nil {
+			t.Errorf("TestBase58 %q: got %v, expected error", s, v)
+		}
+	}
+
+	gen := NewBase58(NewSequential())
+	if s, err := gen.NewID(); err != nil || s != "2" {
+		t.Errorf("TestBase58: got %q (error %v), expected %q", s, err, "2")
+	}
+	if _, err := NewBase58(broken{errTest}).NewID(); err != errTest {
+		t.Errorf("TestBase58: got error %v, expected %v", err, errTest)
+	}
+}
@@ -0,0 +1,135 @@
+package idgen
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// BigInterface is implemented by generators of IDs of arbitrary width, such as layouts
+// wider than 63 bits built with ComposeBig. Like Interface, NewIDs returns the last ID
+// of a batch of n; callers own the returned value.
+type BigInterface interface {
+	NewIDs(n int64) (*big.Int, error)
+}
+
+type bigAdapter struct {
+	gen Interface
+}
+
+// Big adapts gen to BigInterface, e.g. to use it as a BigField.
+func Big(gen Interface) BigInterface {
+	return bigAdapter{gen}
+}
+
+func (b bigAdapter) NewIDs(n int64) (*big.Int, error) {
+	v, err := b.gen.NewIDs(n)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(v), nil
+}
+
+type bigOverflowChecker struct {
+	gen  BigInterface
+	name string
+	bits uint
+}
+
+// NewBigOverflowChecker is like NewOverflowChecker, for BigInterface generators: IDs
+// must be non-negative and fit in allowedBits.
+func NewBigOverflowChecker(allowedBits uint, gen BigInterface) BigInterface {
+	return bigOverflowChecker{gen: gen, name: fmt.Sprintf("%T", gen), bits: allowedBits}
+}
+
+func (o bigOverflowChecker) NewIDs(n int64) (*big.Int, error) {
+	v, err := o.gen.NewIDs(n)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sign() < 0 || uint(v.BitLen()) > o.bits {
+		bit := o.bits
+		if v.Sign() > 0 {
+			bit += new(big.Int).Rsh(v, o.bits).TrailingZeroBits()
+		}
+		return nil, &Error{
+			Generator: o.name,
+			Count:     n,
+			Value:     v.Int64(),
+			Bit:       int(bit),
+			Err:       ErrOverflow,
+		}
+	}
+	return v, nil
+}
+
+type bigShifted struct {
+	gen  BigInterface
+	bits uint
+}
+
+// NewBigShifted is like NewShifted, for BigInterface generators.
+func NewBigShifted(gen BigInterface, bits uint) BigInterface {
+	return bigShifted{gen: gen, bits: bits}
+}
+
+func (s bigShifted) NewIDs(n int64) (*big.Int, error) {
+	v, err := s.gen.NewIDs(n)
+	if err != nil {
+		return nil, err
+	}
+	return v.Lsh(v, s.bits), nil
+}
+
+// BigField is a part of an ID built by ComposeBig.
+type BigField struct {
+	// Name identifies the field in errors.
+	Name string
+	// Bits is the width of the field. Values from Gen must fit in it.
+	Bits uint
+	// Gen generates the values of the field. Use Big for Interface generators.
+	Gen BigInterface
+}
+
+type bigComposed struct {
+	fields []BigInterface
+}
+
+// ComposeBig is like Compose, without the 63-bit limit: e.g. a 128-bit ID of a 64-bit
+// timestamp, a 48-bit worker and a 16-bit sequence. The same caveats about
+// coordination between fields apply.
+func ComposeBig(fields ...BigField) (BigInterface, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("ComposeBig(): at least one field is required")
+	}
+	var total uint
+	for _, f := range fields {
+		if f.Bits == 0 || f.Gen == nil {
+			return nil, fmt.Errorf("ComposeBig(): field %q needs a generator and a width", f.Name)
+		}
+		total += f.Bits
+	}
+	c := &bigComposed{fields: make([]BigInterface, len(fields))}
+	shift := total
+	for i, f := range fields {
+		shift -= f.Bits
+		c.fields[i] = NewBigShifted(NewBigOverflowChecker(f.Bits, f.Gen), shift)
+	}
+	return c, nil
+}
+
+func (c *bigComposed) NewIDs(n int64) (*big.Int, error) {
+	id := new(big.Int)
+	last := len(c.fields) - 1
+	for i, f := range c.fields {
+		count := int64(1)
+		if i == last {
+			count = n
+		}
+		v, err := f.NewIDs(count)
+		if err != nil {
+			return nil, err
+		}
+		id.Or(id, v)
+	}
+	return id, nil
+}
@@ -0,0 +1,81 @@
+package idgen
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestComposeBig(t *testing.T) {
+	t.Parallel()
+	now := int64(1<<62 + 5)
+	gen, err := ComposeBig(
+		BigField{"time", 64, Big(NewTimestampClock(ClockFunc(func() int64 { return now })))},
+		BigField{"worker", 48, Big(NewConstant(0xabcdef))},
+		BigField{"seq", 16, Big(NewSequential())},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := func(seq int64) *big.Int {
+		v := new(big.Int).Lsh(big.NewInt(now/int64(time.Millisecond)), 64)
+		return v.Or(v, big.NewInt(0xabcdef<<16|seq))
+	}
+	var tests = []struct {
+		count    int64
+		expected *big.Int
+		err      error
+	}{
+		{1, expected(1), nil},
+		{1000, expected(1001), nil},
+		{1<<16 - 1002, expected(1<<16 - 1), nil},
+		{1, nil, ErrOverflow},
+	}
+	for i, test := range tests {
+		v, err := gen.NewIDs(test.count)
+		if !errors.Is(err, test.err) || (test.expected == nil) != (v == nil) ||
+			(v != nil && v.Cmp(test.expected) != 0) {
+			t.Errorf("TestComposeBig %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+
+	var invalid = [][]BigField{
+		nil,
+		{{"a", 0, Big(NewSequential())}},
+		{{"a", 8, nil}},
+	}
+	for i, fields := range invalid {
+		if _, err := ComposeBig(fields...); err == nil {
+			t.Errorf("TestComposeBig invalid %d: expected error", i)
+		}
+	}
+}
+
+func TestBigOverflowChecker(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		v   int64
+		bit int
+	}{
+		{255, -1},
+		{256, 8},
+		{0x1400, 10},
+		{-1, 8},
+	}
+	for i, test := range tests {
+		v, err := NewBigOverflowChecker(8, Big(NewConstant(test.v))).NewIDs(1)
+		var e *Error
+		switch {
+		case test.bit < 0 && (err != nil || v.Int64() != test.v):
+			t.Errorf("TestBigOverflowChecker %d: got %v (error %v), expected %v", i, v, err, test.v)
+		case test.bit >= 0 && (!errors.As(err, &e) || e.Bit != test.bit || e.Value != test.v):
+			t.Errorf("TestBigOverflowChecker %d: got %v (error %v), expected overflow at bit %d",
+				i, v, err, test.bit)
+		}
+	}
+	if _, err := Big(broken{errTest}).NewIDs(1); err != errTest {
+		t.Errorf("TestBigOverflowChecker: got error %v, expected %v", err, errTest)
+	}
+}
@@ -0,0 +1,106 @@
+package idgen
+
+import (
+	"fmt"
+	"math"
+	"sync"
+)
+
+// BloomConfig configures NewBloomChecked.
+type BloomConfig struct {
+	// Capacity is how many IDs are expected to be recorded. Past it, the false
+	// positive rate grows.
+	Capacity int64
+	// FalsePositiveRate is the probability of flagging a new ID as a repeat at Capacity,
+	// 0.001 if zero.
+	FalsePositiveRate float64
+	// MaxBytes caps the memory of the filter, trading a higher false positive rate.
+	// Zero means no cap.
+	MaxBytes int64
+	// OnRepeat, if not nil, is called with suspected repeats, which are then returned
+	// as usual. Otherwise NewIDs fails with ErrSuspectedRepeat.
+	OnRepeat func(id int64)
+}
+
+type bloomChecked struct {
+	gen      Interface
+	onRepeat func(id int64)
+	mu       sync.Mutex
+	bits     []uint64
+	// m is the number of bits and k the number of hashes per ID.
+	m, k uint64
+}
+
+// NewBloomChecked wraps gen to record the IDs it issues in a Bloom filter, flagging
+// suspected repeats (e.g. after a clock incident or with an unfamiliar layout). Repeats
+// are never missed, but new IDs may be flagged with the configured false positive rate.
+// The IDs of a batch are checked and recorded one by one (see NewIDRange). Safe for
+// concurrent use.
+func NewBloomChecked(gen Interface, cfg BloomConfig) (Interface, error) {
+	p := cfg.FalsePositiveRate
+	if p == 0 {
+		p = 0.001
+	}
+	if cfg.Capacity < 1 || p <= 0 || p >= 1 || cfg.MaxBytes < 0 {
+		return nil, fmt.Errorf("NewBloomChecked(): invalid config %+v", cfg)
+	}
+	// Optimal sizes: m = -n ln(p) / ln(2)^2 and k = m/n ln(2).
+	n := float64(cfg.Capacity)
+	m := math.Ceil(-n * math.Log(p) / (math.Ln2 * math.Ln2))
+	if max := float64(cfg.MaxBytes * 8); max > 0 && m > max {
+		m = max
+	}
+	m = math.Max(64, math.Ceil(m/64)*64)
+	k := math.Max(1, math.Round(m/n*math.Ln2))
+	return &bloomChecked{
+		gen:      gen,
+		onRepeat: cfg.OnRepeat,
+		bits:     make([]uint64, uint64(m)/64),
+		m:        uint64(m),
+		k:        uint64(k),
+	}, nil
+}
+
+func (b *bloomChecked) NewIDs(n int64) (int64, error) {
+	r, err := b.NewIDRange(n)
+	if err != nil {
+		return 0, err
+	}
+	return r.Last(), nil
+}
+
+// NewIDRange implements RangeInterface.
+func (b *bloomChecked) NewIDRange(n int64) (Range, error) {
+	r, err := NewIDRange(b.gen, n)
+	if err != nil {
+		return Range{}, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id := range r.All() {
+		if b.add(id) {
+			if b.onRepeat == nil {
+				return Range{}, fmt.Errorf("%T.NewIDs(): %d: %w", b.gen, id, ErrSuspectedRepeat)
+			}
+			b.onRepeat(id)
+		}
+	}
+	return r, nil
+}
+
+// add records id, reporting whether it was (probably) already present. It uses double
+// hashing: the i-th bit is h1 + i*h2.
+func (b *bloomChecked) add(id int64) bool {
+	h1 := mix64(uint64(id))
+	h2 := mix64(h1) | 1
+	present := true
+	for i := uint64(0); i < b.k; i++ {
+		bit := (h1 + i*h2) % b.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			present = false
+			b.bits[word] |= mask
+		}
+	}
+	return present
+}
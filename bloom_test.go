@@ -0,0 +1,44 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBloomChecked(t *testing.T) {
+	t.Parallel()
+	var repeats []int64
+	gen, err := NewBloomChecked(NewSequential(), BloomConfig{Capacity: 10000, FalsePositiveRate: 1e-6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := gen.NewIDs(100); err != nil {
+			t.Fatalf("TestBloomChecked: got error %v", err)
+		}
+	}
+
+	cfg := BloomConfig{Capacity: 100, OnRepeat: func(id int64) { repeats = append(repeats, id) }}
+	gen, _ = NewBloomChecked(repeat{}, cfg)
+	for i := 0; i < 3; i++ {
+		if v, err := gen.NewIDs(1); err != nil || v != 1 {
+			t.Errorf("TestBloomChecked: got %v (error %v), expected 1", v, err)
+		}
+	}
+	if len(repeats) != 2 {
+		t.Errorf("TestBloomChecked: got repeats %v, expected 2", repeats)
+	}
+	gen, _ = NewBloomChecked(repeat{}, BloomConfig{Capacity: 100, MaxBytes: 8})
+	gen.NewIDs(1)
+	if _, err := gen.NewIDs(1); !errors.Is(err, ErrSuspectedRepeat) {
+		t.Errorf("TestBloomChecked: got error %v, expected %v", err, ErrSuspectedRepeat)
+	}
+	if b := gen.(*bloomChecked); b.m != 64 {
+		t.Errorf("TestBloomChecked: got %d bits, expected 64 with MaxBytes 8", b.m)
+	}
+	for _, cfg := range []BloomConfig{{}, {Capacity: 1, FalsePositiveRate: 1}, {Capacity: 1, MaxBytes: -1}} {
+		if _, err := NewBloomChecked(repeat{}, cfg); err == nil {
+			t.Errorf("TestBloomChecked: expected error for %+v", cfg)
+		}
+	}
+}
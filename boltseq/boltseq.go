@@ -0,0 +1,137 @@
+// Package boltseq implements a durable sequential ID generator stored in bbolt, for
+// services that need monotonic IDs across restarts without an external database.
+package boltseq
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+	bolt "go.etcd.io/bbolt"
+)
+
+// bucket holds the counters of all sequences.
+var bucket = []byte("idgen")
+
+// Sequential generates IDs by adding to a counter persisted in a bbolt database. To
+// avoid a transaction per call, blocks of batch IDs are reserved at once (checkpoints)
+// and handed out from memory, so up to batch-1 IDs are skipped after a restart. IDs are
+// never reused, even by Sequentials sharing the name in the same database. Safe for
+// concurrent use.
+type Sequential struct {
+	db    *bolt.DB
+	key   []byte
+	batch int64
+
+	mu sync.Mutex
+	// last is the last ID handed out and limit the last ID reserved in the database.
+	last, limit int64
+	closed      bool
+}
+
+var (
+	_ idgen.Interface = (*Sequential)(nil)
+	_ io.Closer       = (*Sequential)(nil)
+)
+
+// New returns a Sequential for the sequence name, resuming from its last checkpoint (or
+// zero, for new sequences). A batch of 1 persists every call.
+func New(db *bolt.DB, name string, batch int64) (*Sequential, error) {
+	if batch < 1 {
+		return nil, fmt.Errorf("boltseq.New(%q): batch must be positive, got %d", name, batch)
+	}
+	s := &Sequential{db: db, key: []byte(name), batch: batch}
+	err := db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return err
+		}
+		if v := b.Get(s.key); v != nil {
+			s.limit = int64(binary.BigEndian.Uint64(v))
+		}
+		return nil
+	})
+	s.last = s.limit
+	return s, err
+}
+
+// NewIDs implements idgen.Interface.
+func (s *Sequential) NewIDs(n int64) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%T.NewIDs() supports count>=1, got %v: %w", s, n, idgen.ErrUnsupportedCount)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return 0, idgen.ErrClosed
+	}
+	if s.last > math.MaxInt64-n {
+		return 0, fmt.Errorf("%T.NewIDs() overflow: %w", s, idgen.ErrOverflow)
+	}
+	if s.last+n > s.limit {
+		if err := s.reserve(n); err != nil {
+			return 0, err
+		}
+	}
+	s.last += n
+	return s.last, nil
+}
+
+// reserve checkpoints a block with room for n more IDs, starting from the stored
+// checkpoint if another Sequential for the same name moved it past this one's.
+func (s *Sequential) reserve(n int64) error {
+	last, limit := s.last, s.limit
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if v := b.Get(s.key); v != nil {
+			if stored := int64(binary.BigEndian.Uint64(v)); stored > limit {
+				// The rest of this block is skipped: batches must be contiguous.
+				last, limit = stored, stored
+			}
+		}
+		if last > math.MaxInt64-n {
+			return fmt.Errorf("%T.NewIDs() overflow: %w", s, idgen.ErrOverflow)
+		}
+		// Reserved blocks are contiguous, so the range spans the old and new blocks.
+		reserve := max(last+n-limit, s.batch)
+		if limit > math.MaxInt64-reserve {
+			reserve = math.MaxInt64 - limit
+		}
+		limit += reserve
+		var v [8]byte
+		binary.BigEndian.PutUint64(v[:], uint64(limit))
+		return b.Put(s.key, v[:])
+	})
+	if err == nil {
+		s.last, s.limit = last, limit
+	}
+	return err
+}
+
+// Close implements io.Closer, checkpointing the last ID handed out so that the next New
+// resumes right after it, without skipping the rest of the reserved block. The checkpoint
+// is left alone if another Sequential for the same name moved it meanwhile. The database
+// is not closed. NewIDs returns idgen.ErrClosed afterwards.
+func (s *Sequential) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if s.last == s.limit {
+		return nil
+	}
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(s.last))
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if cur := b.Get(s.key); cur == nil || int64(binary.BigEndian.Uint64(cur)) != s.limit {
+			return nil
+		}
+		return b.Put(s.key, v[:])
+	})
+}
@@ -0,0 +1,124 @@
+package boltseq
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestSequential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ids.db")
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(db, "orders", 0); err == nil {
+		t.Errorf("expected error for batch 0")
+	}
+	gen, err := New(db, "orders", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		count, expected, limit int64
+	}{
+		{1, 1, 10},
+		{4, 5, 10},
+		{5, 10, 10},
+		{1, 11, 20},
+		{25, 36, 36},
+		{1, 37, 46},
+	}
+	for i, test := range tests {
+		v, err := gen.NewIDs(test.count)
+		switch {
+		case err != nil:
+			t.Errorf("%d: got error %q", i, err)
+		case v != test.expected || gen.limit != test.limit:
+			t.Errorf("%d: got %v (limit %v), expected %v (limit %v)",
+				i, v, gen.limit, test.expected, test.limit)
+		}
+	}
+	if v, err := gen.NewIDs(0); err == nil {
+		t.Errorf("expected error for count 0, got %v", v)
+	}
+	other, err := New(db, "users", 1)
+	if v, err2 := other.NewIDs(1); err != nil || err2 != nil || v != 1 {
+		t.Errorf("independent sequence: got %v (errors %v, %v)", v, err, err2)
+	}
+
+	// Restart: IDs resume after the last checkpoint.
+	db.Close()
+	if db, err = bolt.Open(path, 0o600, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	gen, err = New(db, "orders", 10)
+	if v, err2 := gen.NewIDs(1); err != nil || err2 != nil || v != 47 {
+		t.Errorf("after restart: got %v (errors %v, %v), expected 47", v, err, err2)
+	}
+}
+
+func TestShared(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "ids.db"), 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	a, _ := New(db, "orders", 10)
+	b, _ := New(db, "orders", 4)
+	seen := map[int64]bool{}
+	for i, test := range []struct {
+		gen   *Sequential
+		count int64
+	}{
+		{a, 1}, {b, 1}, {a, 10}, {b, 5}, {a, 3}, {b, 1}, {a, 20}, {b, 12},
+	} {
+		v, err := test.gen.NewIDs(test.count)
+		if err != nil {
+			t.Fatalf("%d: got error %v", i, err)
+		}
+		for id := v - test.count + 1; id <= v; id++ {
+			if seen[id] {
+				t.Errorf("%d: got repeated ID %v", i, id)
+			}
+			seen[id] = true
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "ids.db"), 0o600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	gen, _ := New(db, "orders", 10)
+	gen.NewIDs(3)
+	if err := gen.Close(); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	if _, err := gen.NewIDs(1); err != idgen.ErrClosed {
+		t.Errorf("got error %v, expected %v", err, idgen.ErrClosed)
+	}
+	// The checkpoint was flushed, so no IDs are skipped.
+	gen, _ = New(db, "orders", 10)
+	if v, err := gen.NewIDs(1); err != nil || v != 4 {
+		t.Errorf("after Close: got %v (error %v), expected 4", v, err)
+	}
+	// A Sequential sharing the name reserved past this one's block: Close keeps its
+	// checkpoint, so its IDs are not handed out again.
+	gen, _ = New(db, "orders", 10)
+	gen.NewIDs(1)
+	other, _ := New(db, "orders", 10)
+	last, _ := other.NewIDs(1)
+	if err := gen.Close(); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	gen, _ = New(db, "orders", 10)
+	if v, err := gen.NewIDs(1); err != nil || v <= last {
+		t.Errorf("after concurrent Close: got %v (error %v), expected more than %v", v, err, last)
+	}
+}
@@ -0,0 +1,136 @@
+package idgen
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// CheckDigit is an algorithm for decimal check digits, which catch typos in manually
+// entered IDs.
+type CheckDigit int
+
+// Check digit algorithms.
+const (
+	// Damm detects all single-digit errors and adjacent transpositions.
+	Damm CheckDigit = iota
+	// Luhn (mod 10) detects all single-digit errors and most adjacent transpositions.
+	Luhn
+)
+
+// dammTable is the totally anti-symmetric quasigroup of order 10 used by Damm.
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// checkDigitGen appends a check digit to the IDs of gen.
+type checkDigitGen struct {
+	gen Interface
+	alg CheckDigit
+}
+
+// NewCheckDigit wraps an ID generator to append a check digit to each ID (i.e. the
+// result is 10*id + digit). IDs must not be negative, and an error is returned when the
+// result would overflow. Its NewIDs method only accepts n=1.
+func NewCheckDigit(alg CheckDigit, gen Interface) Interface {
+	return checkDigitGen{gen: gen, alg: alg}
+}
+
+func (c checkDigitGen) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(c, n)
+	}
+	id, err := c.gen.NewIDs(1)
+	if err != nil {
+		return 0, err
+	}
+	return c.alg.Append(id)
+}
+
+// Append returns id with a check digit appended.
+func (alg CheckDigit) Append(id int64) (int64, error) {
+	if id < 0 || id > (math.MaxInt64-9)/10 {
+		return 0, fmt.Errorf("CheckDigit.Append(%d): out of range: %w", id, ErrOverflow)
+	}
+	d, err := alg.Digit(strconv.FormatInt(id, 10))
+	if err != nil {
+		return 0, err
+	}
+	return id*10 + int64(d), nil
+}
+
+// Verify checks the last digit of v and returns the ID without it.
+func (alg CheckDigit) Verify(v int64) (int64, error) {
+	if v < 0 {
+		return 0, fmt.Errorf("CheckDigit.Verify(%d): negative", v)
+	}
+	if _, err := alg.VerifyString(strconv.FormatInt(v, 10)); err != nil {
+		return 0, err
+	}
+	return v / 10, nil
+}
+
+// AppendString returns the decimal string s with a check digit appended.
+func (alg CheckDigit) AppendString(s string) (string, error) {
+	d, err := alg.Digit(s)
+	if err != nil {
+		return "", err
+	}
+	return s + string('0'+d), nil
+}
+
+// VerifyString checks the last digit of s and returns s without it.
+func (alg CheckDigit) VerifyString(s string) (string, error) {
+	if len(s) < 2 {
+		return "", fmt.Errorf("CheckDigit.VerifyString(%q): too short", s)
+	}
+	d, err := alg.Digit(s[:len(s)-1])
+	if err != nil {
+		return "", err
+	}
+	if s[len(s)-1] != '0'+d {
+		return "", fmt.Errorf("CheckDigit.VerifyString(%q): check digit mismatch", s)
+	}
+	return s[:len(s)-1], nil
+}
+
+// Digit computes the check digit of the decimal string s.
+func (alg CheckDigit) Digit(s string) (byte, error) {
+	if s == "" {
+		return 0, fmt.Errorf("CheckDigit.Digit(%q): empty", s)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("CheckDigit.Digit(%q): invalid digit %q", s, s[i])
+		}
+	}
+	switch alg {
+	case Damm:
+		var interim byte
+		for i := 0; i < len(s); i++ {
+			interim = dammTable[interim][s[i]-'0']
+		}
+		return interim, nil
+	case Luhn:
+		var sum int
+		for i := len(s) - 1; i >= 0; i -= 2 {
+			// Doubled digits are the ones at even distance from the check digit.
+			d := int(s[i]-'0') * 2
+			sum += d/10 + d%10
+			if i > 0 {
+				sum += int(s[i-1] - '0')
+			}
+		}
+		return byte((10 - sum%10) % 10), nil
+	}
+	return 0, fmt.Errorf("CheckDigit(%d): unknown algorithm", alg)
+}
@@ -0,0 +1,75 @@
+package idgen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		alg      CheckDigit
+		s        string
+		expected byte
+	}{
+		{Damm, "572", 4},
+		{Damm, "0", 0},
+		{Luhn, "7992739871", 3},
+		{Luhn, "0", 0},
+		{Luhn, "1", 8},
+	}
+	for i, test := range tests {
+		if d, err := test.alg.Digit(test.s); err != nil || d != test.expected {
+			t.Errorf("%d: got %d (error %v), expected %d", i, d, err, test.expected)
+		}
+	}
+
+	for _, alg := range []CheckDigit{Damm, Luhn} {
+		s, err := alg.AppendString("12345")
+		if err != nil {
+			t.Fatalf("%d: got error %q", alg, err)
+		}
+		if v, err := alg.VerifyString(s); err != nil || v != "12345" {
+			t.Errorf("%d: VerifyString(%q): got %q (error %v)", alg, s, v, err)
+		}
+		// Single-digit error and adjacent transposition.
+		for _, typo := range []string{"12845" + s[5:], "13245" + s[5:]} {
+			if v, err := alg.VerifyString(typo); err == nil {
+				t.Errorf("%d: VerifyString(%q): expected error, got %q", alg, typo, v)
+			}
+		}
+		for _, bad := range []string{"", "1", "1a3", "-12"} {
+			if v, err := alg.VerifyString(bad); err == nil {
+				t.Errorf("%d: VerifyString(%q): expected error, got %q", alg, bad, v)
+			}
+		}
+
+		v, err := alg.Append(572)
+		if id, err2 := alg.Verify(v); err != nil || err2 != nil || id != 572 {
+			t.Errorf("%d: Append/Verify: got %d, %d (errors %v, %v)", alg, v, id, err, err2)
+		}
+		for _, id := range []int64{-1, math.MaxInt64 / 10} {
+			if v, err := alg.Append(id); err == nil {
+				t.Errorf("%d: Append(%d): expected error, got %d", alg, id, v)
+			}
+		}
+		if id, err := alg.Verify(-5724); err == nil {
+			t.Errorf("%d: Verify: expected error, got %d", alg, id)
+		}
+	}
+	if v, err := CheckDigit(-1).AppendString("1"); err == nil {
+		t.Errorf("unknown algorithm: expected error, got %q", v)
+	}
+}
+
+func TestCheckDigitGen(t *testing.T) {
+	gen := NewCheckDigit(Damm, &sequential{value: 571})
+	if v, err := gen.NewIDs(1); err != nil || v != 5724 {
+		t.Errorf("got %d (error %v), expected 5724", v, err)
+	}
+	if v, err := gen.NewIDs(2); err == nil {
+		t.Errorf("expected error for n=2, got %d", v)
+	}
+	if _, err := NewCheckDigit(Luhn, broken{errTest}).NewIDs(1); err != errTest {
+		t.Errorf("got error %v, expected %v", err, errTest)
+	}
+}
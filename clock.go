@@ -0,0 +1,62 @@
+package idgen
+
+import "time"
+
+// Clock provides the current time to time-based generators, so they can be tested
+// deterministically or use alternative time sources.
+type Clock interface {
+	// Now returns the current Unix time in Nanoseconds.
+	Now() int64
+}
+
+// ClockFunc adapts a function to Clock.
+type ClockFunc func() int64
+
+// Now implements Clock.
+func (f ClockFunc) Now() int64 {
+	return f()
+}
+
+// SystemClock is the machine clock, used by default. It is a MonotonicClock started
+// with the process, so wall clock steps (e.g. NTP corrections or a VM resuming) never
+// make it go back; use WallClock to follow them instead.
+var SystemClock Clock = NewMonotonicClock()
+
+// WallClock reads the wall clock on every call, so it goes back whenever the machine's
+// time is set back.
+var WallClock Clock = wallClock{}
+
+type wallClock struct{}
+
+func (wallClock) Now() int64 {
+	return time.Now().UnixNano()
+}
+
+// MonotonicClock computes the time as the wall clock at its creation plus the elapsed
+// time measured by the monotonic clock, so it never goes back within a process. The
+// price is that it does not follow corrections either: if the wall clock was wrong at
+// creation, or the machine was suspended (which the monotonic clock may not count), it
+// stays off until the process restarts. And if the wall clock is set back meanwhile, a
+// restarted process may reuse timestamps; WithTimestampStore guards against that. Safe
+// for concurrent use.
+type MonotonicClock struct {
+	start time.Time
+	wall  int64
+}
+
+// NewMonotonicClock returns a MonotonicClock anchored at the current wall clock.
+func NewMonotonicClock() *MonotonicClock {
+	start := time.Now()
+	return &MonotonicClock{start: start, wall: start.UnixNano()}
+}
+
+// Now implements Clock.
+func (c *MonotonicClock) Now() int64 {
+	return c.wall + int64(time.Since(c.start))
+}
+
+// Drift returns how far the wall clock is ahead of c (negative if behind), e.g. to alert
+// when it exceeds the tolerance of the application.
+func (c *MonotonicClock) Drift() time.Duration {
+	return time.Duration(time.Now().UnixNano() - c.Now())
+}
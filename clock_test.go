@@ -0,0 +1,36 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMonotonicClock(t *testing.T) {
+	t.Parallel()
+	c := NewMonotonicClock()
+	// Pretend it was created an hour ago, when the wall clock was 2 hours ahead.
+	c.start = c.start.Add(-time.Hour)
+	c.wall += int64(2 * time.Hour)
+	prev := c.Now()
+	if d := time.Duration(prev - time.Now().UnixNano()); d < 3*time.Hour-time.Minute || d > 3*time.Hour {
+		t.Errorf("TestMonotonicClock: got %v ahead of the wall clock, expected 3h", d)
+	}
+	if d := c.Drift(); d > -3*time.Hour+time.Minute {
+		t.Errorf("TestMonotonicClock: got drift %v, expected -3h", d)
+	}
+	for range 1000 {
+		now := c.Now()
+		if now < prev {
+			t.Fatalf("TestMonotonicClock: got %v after %v", now, prev)
+		}
+		prev = now
+	}
+
+	c = NewMonotonicClock()
+	if d := c.Drift(); d < -time.Second || d > time.Second {
+		t.Errorf("TestMonotonicClock: got drift %v", d)
+	}
+	if d := time.Duration(SystemClock.Now() - WallClock.Now()); d < -time.Second || d > time.Second {
+		t.Errorf("TestMonotonicClock: SystemClock is %v from WallClock", d)
+	}
+}
@@ -0,0 +1,19 @@
+package idgen
+
+import "io"
+
+// Close closes v if it implements io.Closer (like generators with background goroutines,
+// leases or unflushed counters), and does nothing otherwise. It allows deferring cleanup
+// without knowing which implementation is in use:
+//
+//	gen, err := idgen.New(spec)
+//	if err != nil {
+//		return err
+//	}
+//	defer idgen.Close(gen)
+func Close(v any) error {
+	if c, ok := v.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Command idgen generates IDs of the kinds supported by package idgen, for scripts,
+// fixtures and debugging.
+//
+// Usage:
+//
+//	idgen <kind> [flags]
+//
+// Integer kinds (formatted with -f decimal, hex or base62):
+//
+//	snowflake   -node, -epoch (2006-01-02)
+//	sonyflake   -machine
+//	sequential  -start
+//	spec        a spec string for idgen.New, e.g. idgen spec snowflake:node=3
+//
+// String kinds:
+//
+//	uuid        -v 1, 3, 4, 5, 6 or 7; -namespace (dns, url, oid, x500 or a UUID) and
+//	            -name for versions 3 and 5
+//	ulid, ksuid, xid
+//	nanoid      -size, -alphabet
+//
+// All kinds accept -n (how many IDs) and -o lines or csv (with an "id" header).
+package main
+
+import (
+	crand "crypto/rand"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args, returning the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprintln(stderr, "usage: idgen <snowflake|sonyflake|sequential|spec|uuid|ulid|ksuid|xid|nanoid> [flags]")
+		return 2
+	}
+	kind := args[0]
+	fs := flag.NewFlagSet("idgen "+kind, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	n := fs.Int("n", 1, "number of IDs")
+	output := fs.String("o", "lines", "output: lines or csv")
+	var next func() (string, error)
+	var err error
+	switch kind {
+	case "snowflake", "sonyflake", "sequential", "spec":
+		next, err = intKind(kind, fs, args[1:])
+	default:
+		next, err = stringKind(kind, fs, args[1:])
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		fmt.Fprintf(stderr, "idgen %s: %v\n", kind, err)
+		return 2
+	}
+	if err := write(stdout, *output, *n, next); err != nil {
+		fmt.Fprintf(stderr, "idgen %s: %v\n", kind, err)
+		return 1
+	}
+	return 0
+}
+
+// intKind parses the flags of integer kinds, returning a function formatting new IDs.
+func intKind(kind string, fs *flag.FlagSet, args []string) (func() (string, error), error) {
+	format := fs.String("f", "decimal", "format: decimal, hex or base62")
+	var node, machine, start *int64
+	var epoch *string
+	switch kind {
+	case "snowflake":
+		node = fs.Int64("node", 0, "nodeMask, up to 1023")
+		epoch = fs.String("epoch", "", "custom epoch (2006-01-02)")
+	case "sonyflake":
+		machine = fs.Int64("machine", 0, "machine ID, up to 65535")
+	case "sequential":
+		start = fs.Int64("start", 1, "first ID")
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	var spec string
+	switch kind {
+	case "snowflake":
+		spec = fmt.Sprintf("snowflake:node=%d", *node)
+		if *epoch != "" {
+			spec += ",epoch=" + *epoch
+		}
+	case "sonyflake":
+		spec = fmt.Sprintf("sonyflake:machine=%d", *machine)
+	case "sequential":
+		spec = fmt.Sprintf("sequential:start=%d", *start)
+	case "spec":
+		if fs.NArg() != 1 {
+			return nil, errors.New("expected one spec argument")
+		}
+		spec = fs.Arg(0)
+	}
+	var encode func(id int64) string
+	switch *format {
+	case "decimal":
+		encode = func(id int64) string { return strconv.FormatInt(id, 10) }
+	case "hex":
+		encode = func(id int64) string { return strconv.FormatInt(id, 16) }
+	case "base62":
+		// The package's alphabet, so that Prefixed.Parse reads the IDs back.
+		encode = idgen.NewPrefixed("", nil).Format
+	default:
+		return nil, fmt.Errorf("unknown format %q", *format)
+	}
+	gen, err := idgen.New(spec)
+	if err != nil {
+		return nil, err
+	}
+	return func() (string, error) {
+		// Wait for the next timestamp instead of failing when a Snowflake overflows.
+		for {
+			id, err := gen.NewIDs(1)
+			if errors.Is(err, idgen.ErrOverflow) && kind != "sequential" {
+				time.Sleep(time.Millisecond)
+				continue
+			} else if err != nil {
+				return "", err
+			}
+			return encode(id), nil
+		}
+	}, nil
+}
+
+// stringKind parses the flags of string kinds, returning a function formatting new IDs.
+func stringKind(kind string, fs *flag.FlagSet, args []string) (func() (string, error), error) {
+	var version *int
+	var namespace, name, alphabet *string
+	var size *int
+	switch kind {
+	case "uuid":
+		version = fs.Int("v", 4, "version: 1, 3, 4, 5, 6 or 7")
+		namespace = fs.String("namespace", "dns", "namespace for versions 3 and 5")
+		name = fs.String("name", "", "name for versions 3 and 5")
+	case "nanoid":
+		size = fs.Int("size", idgen.NanoIDSize, "length")
+		alphabet = fs.String("alphabet", idgen.NanoIDAlphabet, "characters to use")
+	case "ulid", "ksuid", "xid":
+	default:
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "uuid":
+		return uuidKind(*version, *namespace, *name)
+	case "ulid":
+		m := idgen.NewMonotonicULID(crand.Reader)
+		return func() (string, error) {
+			id, err := m.New()
+			return id.String(), err
+		}, nil
+	case "ksuid":
+		return func() (string, error) {
+			id, err := idgen.NewKSUID(crand.Reader)
+			return id.String(), err
+		}, nil
+	case "xid":
+		machine, err := idgen.NodeFromHostname(24)
+		if err != nil {
+			return nil, err
+		}
+		g, err := idgen.NewXIDGenerator(machine)
+		if err != nil {
+			return nil, err
+		}
+		return func() (string, error) {
+			return g.New().String(), nil
+		}, nil
+	default:
+		return func() (string, error) {
+			return idgen.NewNanoIDCustom(*alphabet, *size)
+		}, nil
+	}
+}
+
+func uuidKind(version int, namespace, name string) (func() (string, error), error) {
+	var gen func() (idgen.UUID, error)
+	switch version {
+	case 1:
+		gen = idgen.NewUUIDv1
+	case 4:
+		gen = idgen.NewUUIDv4Crypto
+	case 6:
+		gen = idgen.NewUUIDv6
+	case 7:
+		gen = idgen.NewUUIDv7
+	case 3, 5:
+		ns, err := parseNamespace(namespace)
+		if err != nil {
+			return nil, err
+		}
+		newHash := idgen.NewUUIDv3
+		if version == 5 {
+			newHash = idgen.NewUUIDv5
+		}
+		gen = func() (idgen.UUID, error) {
+			return newHash(ns, []byte(name)), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported UUID version %d", version)
+	}
+	return func() (string, error) {
+		id, err := gen()
+		return id.String(), err
+	}, nil
+}
+
+func parseNamespace(s string) (idgen.UUID, error) {
+	switch s {
+	case "dns":
+		return idgen.NamespaceDNS, nil
+	case "url":
+		return idgen.NamespaceURL, nil
+	case "oid":
+		return idgen.NamespaceOID, nil
+	case "x500":
+		return idgen.NamespaceX500, nil
+	}
+	return idgen.ParseUUID(s)
+}
+
+// write writes n IDs from next to w in the given output format.
+func write(w io.Writer, output string, n int, next func() (string, error)) error {
+	var cw *csv.Writer
+	switch output {
+	case "lines":
+	case "csv":
+		cw = csv.NewWriter(w)
+		cw.Write([]string{"id"})
+	default:
+		return fmt.Errorf("unknown output %q", output)
+	}
+	for i := 0; i < n; i++ {
+		id, err := next()
+		if err != nil {
+			return err
+		}
+		if cw != nil {
+			err = cw.Write([]string{id})
+		} else {
+			_, err = io.WriteString(w, id+"\n")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if cw != nil {
+		cw.Flush()
+		return cw.Error()
+	}
+	return nil
+}
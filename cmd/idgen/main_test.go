@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var tests = []struct {
+		args   []string
+		status int
+		match  string
+	}{
+		{[]string{"sequential", "-n", "3", "-start", "10"}, 0, `^10\n11\n12\n$`},
+		{[]string{"sequential", "-start", "255", "-f", "hex", "-n", "2"}, 0, `^ff\n100\n$`},
+		{[]string{"sequential", "-start", "61", "-f", "base62", "-n", "2"}, 0, `^z\n10\n$`},
+		{[]string{"sequential", "-start", "-1", "-f", "base62"}, 0, `^LygHa16AHYF\n$`},
+		{[]string{"sequential", "-o", "csv", "-n", "2"}, 0, `^id\n1\n2\n$`},
+		{[]string{"spec", "sequential:start=7"}, 0, `^7\n$`},
+		{[]string{"snowflake", "-node", "3", "-n", "100"}, 0, `^(\d+\n){100}$`},
+		{[]string{"snowflake", "-node", "3", "-epoch", "2020-01-01"}, 0, `^\d+\n$`},
+		{[]string{"sonyflake", "-machine", "7"}, 0, `^\d+\n$`},
+		{[]string{"uuid", "-v", "7", "-n", "2"}, 0, `^([0-9a-f-]{14}7[0-9a-f-]{21}\n){2}$`},
+		{[]string{"uuid", "-v", "5", "-name", "example.com"}, 0, "^cfbff0d1-9375-5685-968c-48ce8b15ae17\n$"},
+		{[]string{"uuid", "-v", "1"}, 0, `^[0-9a-f-]{14}1`},
+		{[]string{"uuid", "-v", "4"}, 0, `^[0-9a-f-]{14}4`},
+		{[]string{"uuid", "-v", "6"}, 0, `^[0-9a-f-]{14}6`},
+		{[]string{"ulid", "-n", "2"}, 0, `^([0-9A-Z]{26}\n){2}$`},
+		{[]string{"ksuid"}, 0, `^[0-9A-Za-z]{27}\n$`},
+		{[]string{"xid"}, 0, `^[0-9a-v]{20}\n$`},
+		{[]string{"nanoid", "-size", "8", "-alphabet", "ab"}, 0, `^[ab]{8}\n$`},
+		{[]string{}, 2, `^$`},
+		{[]string{"snowflake", "-node", "1024"}, 2, `^$`},
+		{[]string{"sequential", "-f", "octal"}, 2, `^$`},
+		{[]string{"sequential", "-o", "xml"}, 1, `^$`},
+		{[]string{"uuid", "-v", "2"}, 2, `^$`},
+		{[]string{"spec"}, 2, `^$`},
+		{[]string{"guid"}, 2, `^$`},
+	}
+	for i, test := range tests {
+		var stdout, stderr bytes.Buffer
+		status := run(test.args, &stdout, &stderr)
+		if status != test.status || !regexp.MustCompile(test.match).MatchString(stdout.String()) {
+			t.Errorf("TestRun %d (%s): got status %d, output %q (stderr %q)",
+				i, strings.Join(test.args, " "), status, stdout.String(), stderr.String())
+		}
+	}
+}
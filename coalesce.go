@@ -0,0 +1,84 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+)
+
+type coalescer struct {
+	gen      Interface
+	maxBatch int
+	mu       sync.Mutex
+	// busy is set while a leader has a batch in flight; callers arriving meanwhile
+	// queue up for the next one.
+	busy  bool
+	queue []chan coalesced
+}
+
+// coalesced is delivered to a queued caller: either its ID (or the batch error) or, with
+// lead set, the request to issue the next batch itself.
+type coalesced struct {
+	id   int64
+	err  error
+	lead bool
+}
+
+// NewCoalescer wraps gen so that concurrent NewIDs(1) calls are merged into a single
+// batch of up to maxBatch IDs: while one batch is in flight, new callers queue up and
+// the first of them then requests IDs for all, in arrival order. It pays off in front of
+// generators with a costly round trip (locks under contention, remote allocators), and
+// gen must accept n > 1. When gen fails, every caller in the batch gets the error.
+// Its NewIDs method only accepts n=1. Safe for concurrent use.
+func NewCoalescer(gen Interface, maxBatch int) (Interface, error) {
+	if maxBatch < 1 {
+		return nil, fmt.Errorf("NewCoalescer(%d): maxBatch must be positive", maxBatch)
+	}
+	return &coalescer{gen: gen, maxBatch: maxBatch}, nil
+}
+
+func (c *coalescer) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(c, n)
+	}
+	w := make(chan coalesced, 1)
+	c.mu.Lock()
+	c.queue = append(c.queue, w)
+	if c.busy {
+		c.mu.Unlock()
+		if r := <-w; !r.lead {
+			return r.id, r.err
+		}
+	} else {
+		c.busy = true
+		c.mu.Unlock()
+	}
+	c.lead()
+	r := <-w
+	return r.id, r.err
+}
+
+// lead issues one batch for the callers at the head of the queue, which include the
+// current one, and hands leadership over to the next caller waiting, if any.
+func (c *coalescer) lead() {
+	c.mu.Lock()
+	batch := c.queue[:min(len(c.queue), c.maxBatch)]
+	c.queue = c.queue[len(batch):]
+	c.mu.Unlock()
+
+	r, err := NewIDRange(c.gen, int64(len(batch)))
+	for i, w := range batch {
+		if err != nil {
+			w <- coalesced{err: err}
+		} else {
+			w <- coalesced{id: r.First() + int64(i)*r.Step()}
+		}
+	}
+
+	c.mu.Lock()
+	if len(c.queue) == 0 {
+		c.busy = false
+	} else {
+		c.queue[0] <- coalesced{lead: true}
+	}
+	c.mu.Unlock()
+}
@@ -0,0 +1,116 @@
+package idgen
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gated is a sequential generator that blocks each call until released, recording
+// the batch sizes requested.
+type gated struct {
+	release chan struct{}
+	mu      sync.Mutex
+	last    int64
+	sizes   []int64
+}
+
+func (g *gated) NewIDs(n int64) (int64, error) {
+	<-g.release
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sizes = append(g.sizes, n)
+	g.last += n
+	return g.last, nil
+}
+
+func TestCoalescer(t *testing.T) {
+	t.Parallel()
+	g := &gated{release: make(chan struct{})}
+	gen, err := NewCoalescer(g, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := gen.(*coalescer)
+	ids := make(chan int64, 6)
+	get := func() {
+		v, err := gen.NewIDs(1)
+		if err != nil {
+			t.Error(err)
+		}
+		ids <- v
+	}
+	waitQueue := func(n int) {
+		for start := time.Now(); ; time.Sleep(time.Millisecond) {
+			c.mu.Lock()
+			l, busy := len(c.queue), c.busy
+			c.mu.Unlock()
+			if busy && l == n {
+				return
+			}
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("TestCoalescer: got %d queued, expected %d", l, n)
+			}
+		}
+	}
+	go get()
+	waitQueue(0) // The first caller leads a batch of its own.
+	for i := 1; i <= 5; i++ {
+		go get()
+		waitQueue(i)
+	}
+	close(g.release)
+	var got []int64
+	for range 6 {
+		got = append(got, <-ids)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	for i, v := range got {
+		if v != int64(i+1) {
+			t.Errorf("TestCoalescer: got IDs %v", got)
+			break
+		}
+	}
+	if len(g.sizes) != 3 || g.sizes[0] != 1 || g.sizes[1] != 3 || g.sizes[2] != 2 {
+		t.Errorf("TestCoalescer: got batch sizes %v, expected [1 3 2]", g.sizes)
+	}
+	if _, err := gen.NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestCoalescer: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+	if _, err := NewCoalescer(g, 0); err == nil {
+		t.Error("TestCoalescer: expected error for maxBatch 0")
+	}
+}
+
+func TestCoalescerConcurrent(t *testing.T) {
+	t.Parallel()
+	gen, _ := NewCoalescer(NewSequential(), 16)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = map[int64]bool{}
+	)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				v, err := gen.NewIDs(1)
+				mu.Lock()
+				if err != nil || seen[v] {
+					t.Errorf("TestCoalescerConcurrent: got %v (error %v)", v, err)
+				}
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	gen, _ = NewCoalescer(broken{errTest}, 16)
+	if v, err := gen.NewIDs(1); err != errTest {
+		t.Errorf("TestCoalescerConcurrent: got %v (error %v), expected %v", v, err, errTest)
+	}
+}
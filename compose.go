@@ -0,0 +1,66 @@
+package idgen
+
+import "fmt"
+
+// Field is a part of an ID built by Compose.
+type Field struct {
+	// Name identifies the field in errors.
+	Name string
+	// Bits is the width of the field. Values from Gen must fit in it.
+	Bits byte
+	// Gen generates the values of the field.
+	Gen Interface
+}
+
+type composed struct {
+	fields []Interface
+	names  []string
+}
+
+// Compose returns an ID generator that places the values of fields side by side, from
+// the most significant to the least significant bits, wrapping each one with
+// NewOverflowChecker and NewShifted. Only the last field receives NewIDs' n (so it
+// should be a sequence, and batches are contiguous); the others are asked for one value
+// per call. Fields are not coordinated: for a timestamp and a sequence that restarts on
+// each new timestamp, use Layout.NewSnowflake. Widths must add up to at most 63 bits,
+// so IDs are never negative.
+func Compose(fields ...Field) (Interface, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("Compose(): at least one field is required")
+	}
+	var total int
+	for _, f := range fields {
+		if f.Bits == 0 || f.Gen == nil {
+			return nil, fmt.Errorf("Compose(): field %q needs a generator and a width", f.Name)
+		}
+		total += int(f.Bits)
+	}
+	if total > 63 {
+		return nil, fmt.Errorf("Compose(): %d bits, at most 63 allowed", total)
+	}
+	c := &composed{fields: make([]Interface, len(fields)), names: make([]string, len(fields))}
+	shift := byte(total)
+	for i, f := range fields {
+		shift -= f.Bits
+		c.fields[i] = NewShifted(NewOverflowChecker(f.Bits, f.Gen), shift)
+		c.names[i] = f.Name
+	}
+	return c, nil
+}
+
+func (c *composed) NewIDs(n int64) (int64, error) {
+	var id int64
+	last := len(c.fields) - 1
+	for i, f := range c.fields {
+		count := int64(1)
+		if i == last {
+			count = n
+		}
+		v, err := f.NewIDs(count)
+		if err != nil {
+			return 0, fmt.Errorf("Compose field %q: %w", c.names[i], err)
+		}
+		id |= v
+	}
+	return id, nil
+}
@@ -0,0 +1,53 @@
+package idgen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+	gen, err := Compose(
+		Field{"region", 4, NewConstant(9)},
+		Field{"shard", 6, NewConstant(33)},
+		Field{"seq", 10, NewSequential()},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		count, expected int64
+		err             error
+	}{
+		{1, 9<<16 | 33<<10 | 1, nil},
+		{1000, 9<<16 | 33<<10 | 1001, nil},
+		{22, 9<<16 | 33<<10 | 1023, nil},
+		{1, 0, ErrOverflow},
+	}
+	for i, test := range tests {
+		v, err := gen.NewIDs(test.count)
+		if !errors.Is(err, test.err) || v != test.expected {
+			t.Errorf("TestCompose %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+
+	var invalid = [][]Field{
+		nil,
+		{{"a", 0, NewSequential()}},
+		{{"a", 8, nil}},
+		{{"a", 32, NewConstant(1)}, {"b", 32, NewSequential()}},
+	}
+	for i, fields := range invalid {
+		if _, err := Compose(fields...); err == nil {
+			t.Errorf("TestCompose invalid %d: expected error", i)
+		}
+	}
+	gen, _ = Compose(Field{"node", 2, NewConstant(4)}, Field{"seq", 4, NewSequential()})
+	_, err = gen.NewIDs(1)
+	if e := (*Error)(nil); !errors.As(err, &e) || e.Err != ErrOverflow ||
+		!strings.Contains(err.Error(), `"node"`) {
+		t.Errorf("TestCompose: got error %v, expected %v in field node", err, ErrOverflow)
+	}
+}
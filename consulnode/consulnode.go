@@ -0,0 +1,121 @@
+// Package consulnode allocates Snowflake nodeMasks using Consul sessions and KV locks.
+package consulnode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/hashicorp/consul/api"
+)
+
+// Allocator claims a nodeMask by acquiring the key prefix+nodeMask with a session, which
+// is renewed in the background. The session deletes its keys when destroyed or expired,
+// so the nodeMask becomes free again if the process dies. If the session cannot be
+// renewed, Done is closed. Safe for concurrent use.
+type Allocator struct {
+	client   *api.Client
+	prefix   string
+	nodeBits byte
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	key     string
+	session string
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+var (
+	_ idgen.NodeAllocator = (*Allocator)(nil)
+	_ io.Closer           = (*Allocator)(nil)
+)
+
+// New returns an Allocator for nodeMasks of nodeBits (e.g.
+// idgen.SnowflakeLayout.NodeBits), using keys under prefix (e.g. "idgen/myservice/").
+// Consul requires the ttl to be between 10 Seconds and 24 hours.
+func New(client *api.Client, prefix string, nodeBits byte, ttl time.Duration) *Allocator {
+	return &Allocator{client: client, prefix: prefix, nodeBits: nodeBits, ttl: ttl}
+}
+
+// Acquire implements idgen.NodeAllocator, claiming the lowest free nodeMask.
+func (a *Allocator) Acquire(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop != nil {
+		return 0, errors.New("consulnode: already acquired")
+	}
+	w := (&api.WriteOptions{}).WithContext(ctx)
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s/%d", host, os.Getpid())
+	session, _, err := a.client.Session().Create(&api.SessionEntry{
+		Name:     "idgen " + owner,
+		TTL:      a.ttl.String(),
+		Behavior: api.SessionBehaviorDelete,
+	}, w)
+	if err != nil {
+		return 0, err
+	}
+
+	for node := int64(0); node < 1<<a.nodeBits; node++ {
+		key := a.prefix + strconv.FormatInt(node, 10)
+		ok, _, err := a.client.KV().Acquire(&api.KVPair{
+			Key:     key,
+			Value:   []byte(owner),
+			Session: session,
+		}, w)
+		if err != nil {
+			a.client.Session().Destroy(session, nil)
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		a.key, a.session, a.stop, a.done = key, session, make(chan struct{}), make(chan struct{})
+		go func(stop, done chan struct{}) {
+			defer close(done)
+			// Returns when stop is closed or the session is no longer valid.
+			a.client.Session().RenewPeriodic(a.ttl.String(), session, nil, stop)
+		}(a.stop, a.done)
+		return node, nil
+	}
+	a.client.Session().Destroy(session, nil)
+	return 0, idgen.ErrNoFreeNode
+}
+
+// Release implements idgen.NodeAllocator, releasing the key and destroying the session.
+// An explicit release is not subject to Consul's lock-delay, which keeps the nodeMask
+// unavailable for a while (15 Seconds by default) when the session expires instead.
+func (a *Allocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stop == nil {
+		return nil
+	}
+	close(a.stop)
+	a.stop = nil
+	w := (&api.WriteOptions{}).WithContext(ctx)
+	_, _, err := a.client.KV().Release(&api.KVPair{Key: a.key, Session: a.session}, w)
+	if _, err2 := a.client.Session().Destroy(a.session, w); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// Done implements idgen.NodeAllocator. It returns nil before Acquire.
+func (a *Allocator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done
+}
+
+// Close implements io.Closer, releasing the nodeMask (see Release).
+func (a *Allocator) Close() error {
+	return a.Release(context.Background())
+}
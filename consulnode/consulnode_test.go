@@ -0,0 +1,47 @@
+package consulnode
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/hashicorp/consul/api"
+)
+
+// TestAllocator requires a Consul agent, e.g. CONSUL_HTTP_ADDR=localhost:8500.
+func TestAllocator(t *testing.T) {
+	if os.Getenv("CONSUL_HTTP_ADDR") == "" {
+		t.Skip("CONSUL_HTTP_ADDR not set")
+	}
+	client, err := api.NewClient(api.DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	prefix := "idgen-test/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "/"
+	ttl := 10 * time.Second
+
+	a, b, c := New(client, prefix, 1, ttl), New(client, prefix, 1, ttl), New(client, prefix, 1, ttl)
+	nodeA, errA := a.Acquire(ctx)
+	nodeB, errB := b.Acquire(ctx)
+	switch {
+	case errA != nil || errB != nil:
+		t.Fatalf("got errors %v, %v", errA, errB)
+	case nodeA == nodeB:
+		t.Fatalf("got same node %v", nodeA)
+	}
+	if node, err := c.Acquire(ctx); err != idgen.ErrNoFreeNode {
+		t.Errorf("got %v (error %v), expected error %v", node, err, idgen.ErrNoFreeNode)
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Errorf("got error %q", err)
+	}
+	if node, err := c.Acquire(ctx); err != nil || node != nodeA {
+		t.Errorf("got %v (error %v), expected %v", node, err, nodeA)
+	}
+	b.Release(ctx)
+	c.Release(ctx)
+}
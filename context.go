@@ -0,0 +1,38 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ContextInterface is implemented by generators that may wait (for the clock, a
+// backend or a rate limit) and can give up when a context is done.
+type ContextInterface interface {
+	NewIDsContext(ctx context.Context, n int64) (int64, error)
+}
+
+// NewIDsContext generates n IDs with gen, honoring ctx if gen implements
+// ContextInterface. Other generators are only called if ctx is not done yet. Errors for
+// deadlines are ErrDeadline, or the generator's own.
+func NewIDsContext(ctx context.Context, gen Interface, n int64) (int64, error) {
+	if c, ok := gen.(ContextInterface); ok {
+		return c.NewIDsContext(ctx, n)
+	}
+	if err := ctx.Err(); err != nil {
+		return 0, contextError(err)
+	}
+	return gen.NewIDs(n)
+}
+
+// contextError maps errors for passed deadlines (from ctx.Err or a backend honoring ctx)
+// to ErrDeadline, keeping the others.
+func contextError(err error) error {
+	switch {
+	case err == context.DeadlineExceeded:
+		return ErrDeadline
+	case errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, ErrDeadline):
+		return fmt.Errorf("%w: %v", ErrDeadline, err)
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestSnowflakeMaxWait(t *testing.T) {
+	t.Parallel()
+	now := int64(10 * time.Millisecond)
+	clock := WithClock(ClockFunc(func() int64 { return now }))
+	gen := NewSnowflake(1, clock, WithWaitOnOverflow(), WithWaitOnClockRollback(),
+		WithMaxWait(time.Millisecond))
+	if _, err := gen.NewIDs(4096); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	if v, err := gen.NewIDs(1); err != ErrWouldBlock {
+		t.Errorf("TestSnowflakeMaxWait: got %v (error %v), expected %v", v, err, ErrWouldBlock)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Microsecond || elapsed > time.Second {
+		t.Errorf("TestSnowflakeMaxWait: waited %v, expected about 1ms", elapsed)
+	}
+
+	now -= int64(time.Millisecond)
+	if v, err := gen.NewIDs(1); err != ErrWouldBlock {
+		t.Errorf("TestSnowflakeMaxWait: got %v (error %v), expected %v", v, err, ErrWouldBlock)
+	}
+}
+
+func TestNewIDsContext(t *testing.T) {
+	t.Parallel()
+	now := int64(10 * time.Millisecond)
+	gen := NewSnowflake(1, WithClock(ClockFunc(func() int64 { return now })), WithWaitOnOverflow())
+	if _, err := gen.NewIDs(4096); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Millisecond)
+	defer cancel()
+	v, err := NewIDsContext(ctx, gen, 1)
+	if err != ErrDeadline || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected %v", v, err, ErrDeadline)
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if v, err := NewIDsContext(canceled, gen, 1); err != context.Canceled {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected %v", v, err, context.Canceled)
+	}
+	now += int64(time.Millisecond)
+	if v, err := NewIDsContext(ctx, gen, 1); err != nil || v != 11<<22|1<<12 {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected %v", v, err, 11<<22|1<<12)
+	}
+
+	if v, err := NewIDsContext(canceled, NewSequential(), 1); err != context.Canceled {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected %v", v, err, context.Canceled)
+	}
+	if v, err := NewIDsContext(context.Background(), NewSequential(), 1); err != nil || v != 1 {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected 1", v, err)
+	}
+
+	type key struct{}
+	store := SegmentStoreFunc(func(ctx context.Context, n int64) (int64, error) {
+		if ctx.Value(key{}) == nil {
+			return 0, errTest
+		}
+		return n, nil
+	})
+	seg, _ := NewSegment(store, 10, 1)
+	if v, err := NewIDsContext(context.WithValue(ctx, key{}, true), seg, 1); err != nil || v != 1 {
+		t.Errorf("TestNewIDsContext: got %v (error %v), expected 1", v, err)
+	}
+}
+
+func TestSegmentContext(t *testing.T) {
+	t.Parallel()
+	store := &segmentStore{}
+	seg, _ := NewSegment(store, 2, 1)
+	// The first segment is fetched synchronously, the second one hangs in the background.
+	seg.NewIDs(1)
+	store.Lock()
+	store.gate = make(chan struct{})
+	store.Unlock()
+	seg.NewIDs(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if v, err := NewIDsContext(ctx, seg, 1); err != ErrDeadline {
+		t.Errorf("TestSegmentContext: got %v (error %v), expected %v", v, err, ErrDeadline)
+	}
+	close(store.gate)
+	if v, err := seg.NewIDs(1); err != nil || v != 3 {
+		t.Errorf("TestSegmentContext: got %v (error %v), expected 3", v, err)
+	}
+	seg.(io.Closer).Close()
+
+	deadline := SegmentStoreFunc(func(ctx context.Context, n int64) (int64, error) {
+		return 0, fmt.Errorf("store: %w", context.DeadlineExceeded)
+	})
+	seg, _ = NewSegment(deadline, 2, 0.5)
+	if v, err := seg.NewIDs(1); !errors.Is(err, ErrDeadline) {
+		t.Errorf("TestSegmentContext: got %v (error %v), expected %v", v, err, ErrDeadline)
+	}
+}
@@ -0,0 +1,197 @@
+package idgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// crockford is Crockford's Base32 alphabet (no I, L, O, U).
+	crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// crockfordCheck has the additional check symbols, for values 32 to 36.
+	crockfordCheck = crockford + "*~$=U"
+)
+
+// EncodeCrockford returns v in Crockford's Base32, without leading zeros. Negative values
+// are encoded as their 64-bit two's complement.
+func EncodeCrockford(v int64) string {
+	var s [13]byte
+	u := uint64(v)
+	i := len(s)
+	for {
+		i--
+		s[i] = crockford[u&0x1f]
+		if u >>= 5; u == 0 {
+			break
+		}
+	}
+	return string(s[i:])
+}
+
+// EncodeCrockfordCheck is like EncodeCrockford, appending a check symbol.
+func EncodeCrockfordCheck(v int64) string {
+	return EncodeCrockford(v) + string(crockfordCheck[uint64(v)%37])
+}
+
+// DecodeCrockford decodes a value encoded by EncodeCrockford. Decoding is case-insensitive,
+// hyphens are ignored, I and L are read as 1 and O as 0.
+func DecodeCrockford(s string) (int64, error) {
+	n, err := crockfordNormalize(s)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for i := 0; i < len(n); i++ {
+		if u>>59 != 0 {
+			return 0, fmt.Errorf("DecodeCrockford(%q): overflow", s)
+		}
+		u = u<<5 | uint64(strings.IndexByte(crockford, n[i]))
+	}
+	return int64(u), nil
+}
+
+// DecodeCrockfordCheck decodes a value encoded by EncodeCrockfordCheck, returning an error
+// if the check symbol does not match.
+func DecodeCrockfordCheck(s string) (int64, error) {
+	data, check, err := crockfordSplitCheck(s)
+	if err != nil {
+		return 0, err
+	}
+	v, err := DecodeCrockford(data)
+	if err != nil {
+		return 0, err
+	}
+	if uint64(v)%37 != uint64(check) {
+		return 0, fmt.Errorf("DecodeCrockfordCheck(%q): check symbol mismatch", s)
+	}
+	return v, nil
+}
+
+// EncodeCrockford128 returns a 128-bit big-endian value in Crockford's Base32, always 26
+// characters long (the first character is at most 7), like ULIDs.
+func EncodeCrockford128(id [16]byte) string {
+	// 128 bits are encoded as 26 groups of 5 bits, the first group having only 3.
+	var s [26]byte
+	var acc uint32
+	var bits uint
+	j := len(s) - 1
+	for i := len(id) - 1; i >= 0; i-- {
+		acc |= uint32(id[i]) << bits
+		for bits += 8; bits >= 5; bits -= 5 {
+			s[j] = crockford[acc&0x1f]
+			acc >>= 5
+			j--
+		}
+	}
+	s[0] = crockford[acc&0x1f]
+	return string(s[:])
+}
+
+// EncodeCrockford128Check is like EncodeCrockford128, appending a check symbol.
+func EncodeCrockford128Check(id [16]byte) string {
+	return EncodeCrockford128(id) + string(crockfordCheck[mod37(id)])
+}
+
+// DecodeCrockford128 decodes a 128-bit value encoded by EncodeCrockford128, with the same
+// normalization as DecodeCrockford. After normalization, exactly 26 characters are
+// expected.
+func DecodeCrockford128(s string) ([16]byte, error) {
+	var id [16]byte
+	n, err := crockfordNormalize(s)
+	if err != nil {
+		return id, err
+	}
+	if len(n) != 26 {
+		return id, fmt.Errorf("DecodeCrockford128(%q): expected length 26, got %d", s, len(n))
+	}
+	if n[0] > '7' {
+		return id, fmt.Errorf("DecodeCrockford128(%q): overflow", s)
+	}
+	var acc uint32
+	var bits uint
+	j := len(id) - 1
+	for i := len(n) - 1; i >= 0 && j >= 0; i-- {
+		acc |= uint32(strings.IndexByte(crockford, n[i])) << bits
+		for bits += 5; bits >= 8 && j >= 0; bits -= 8 {
+			id[j] = byte(acc)
+			acc >>= 8
+			j--
+		}
+	}
+	return id, nil
+}
+
+// DecodeCrockford128Check decodes a value encoded by EncodeCrockford128Check, returning
+// an error if the check symbol does not match.
+func DecodeCrockford128Check(s string) ([16]byte, error) {
+	data, check, err := crockfordSplitCheck(s)
+	if err != nil {
+		return [16]byte{}, err
+	}
+	id, err := DecodeCrockford128(data)
+	if err != nil {
+		return id, err
+	}
+	if mod37(id) != check {
+		return [16]byte{}, fmt.Errorf("DecodeCrockford128Check(%q): check symbol mismatch", s)
+	}
+	return id, nil
+}
+
+// crockfordNormalize removes hyphens, converts to upper case and maps ambiguous
+// characters, validating the result.
+func crockfordNormalize(s string) (string, error) {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' {
+			c -= 'a' - 'A'
+		}
+		switch c {
+		case '-':
+			continue
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		if strings.IndexByte(crockford, c) < 0 {
+			return "", fmt.Errorf("Crockford(%q): invalid character %q", s, s[i])
+		}
+		b = append(b, c)
+	}
+	if len(b) == 0 {
+		return "", fmt.Errorf("Crockford(%q): empty", s)
+	}
+	return string(b), nil
+}
+
+// crockfordSplitCheck separates the check symbol from the data.
+func crockfordSplitCheck(s string) (string, int, error) {
+	if len(s) < 2 {
+		return "", 0, fmt.Errorf("Crockford(%q): too short for check symbol", s)
+	}
+	c := s[len(s)-1]
+	if 'a' <= c && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	switch c {
+	case 'I', 'L':
+		c = '1'
+	case 'O':
+		c = '0'
+	}
+	check := strings.IndexByte(crockfordCheck, c)
+	if check < 0 {
+		return "", 0, fmt.Errorf("Crockford(%q): invalid check symbol %q", s, s[len(s)-1])
+	}
+	return s[:len(s)-1], check, nil
+}
+
+func mod37(id [16]byte) int {
+	var m int
+	for _, b := range id {
+		m = (m<<8 + int(b)) % 37
+	}
+	return m
+}
@@ -0,0 +1,87 @@
+package idgen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCrockford(t *testing.T) {
+	tests := []struct {
+		v        int64
+		s, check string
+	}{
+		{0, "0", "00"},
+		{1, "1", "11"},
+		{32, "10", "10*"},
+		{36, "14", "14U"},
+		{1234, "16J", "16JD"},
+		{math.MaxInt64, "7ZZZZZZZZZZZZ", "7ZZZZZZZZZZZZ5"},
+		{-1, "FZZZZZZZZZZZZ", "FZZZZZZZZZZZZB"},
+	}
+	for i, test := range tests {
+		if s := EncodeCrockford(test.v); s != test.s {
+			t.Errorf("%d: encode, got %s, expected %s", i, s, test.s)
+		}
+		if s := EncodeCrockfordCheck(test.v); s != test.check {
+			t.Errorf("%d: encode with check, got %s, expected %s", i, s, test.check)
+		}
+		if v, err := DecodeCrockford(test.s); err != nil || v != test.v {
+			t.Errorf("%d: decode, got %d (error %v), expected %d", i, v, err, test.v)
+		}
+		if v, err := DecodeCrockfordCheck(test.check); err != nil || v != test.v {
+			t.Errorf("%d: decode with check, got %d (error %v), expected %d", i, v, err, test.v)
+		}
+	}
+
+	// Normalization of transcribed values.
+	for _, s := range []string{"16j", "1-6-J", "i6J", "l6j"} {
+		if v, err := DecodeCrockford(s); err != nil || v != 1234 {
+			t.Errorf("DecodeCrockford(%q): got %d (error %v), expected 1234", s, v, err)
+		}
+	}
+	if v, err := DecodeCrockfordCheck("14u"); err != nil || v != 36 {
+		t.Errorf("DecodeCrockfordCheck: got %d (error %v), expected 36", v, err)
+	}
+	for _, s := range []string{"", "-", "16U", "1*", "G0000000000000"} {
+		if v, err := DecodeCrockford(s); err == nil {
+			t.Errorf("DecodeCrockford(%q): expected error, got %d", s, v)
+		}
+	}
+	for _, s := range []string{"", "1", "16JT", "16J#", "U"} {
+		if v, err := DecodeCrockfordCheck(s); err == nil {
+			t.Errorf("DecodeCrockfordCheck(%q): expected error, got %d", s, v)
+		}
+	}
+}
+
+func TestCrockford128(t *testing.T) {
+	id := [16]byte{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x8c, 0x5b, 0x2e, 0x0d, 0xd7, 0x3f, 0x1c,
+		0x84, 0x3c, 0x91, 0x5f}
+	s := EncodeCrockford128(id)
+	check := EncodeCrockford128Check(id)
+	switch {
+	case s != "01ARYZ6S4CBCQ0VNSZ3J23S4AZ":
+		t.Errorf("encode, got %s", s)
+	case check[:26] != s || len(check) != 27:
+		t.Errorf("encode with check, got %s", check)
+	}
+	if v, err := DecodeCrockford128("01aryz6s4cbcq0vnsz3j23s4az"); err != nil || v != id {
+		t.Errorf("decode, got %v (error %v), expected %v", v, err, id)
+	}
+	if v, err := DecodeCrockford128Check(check); err != nil || v != id {
+		t.Errorf("decode with check, got %v (error %v), expected %v", v, err, id)
+	}
+	if v, err := ParseULID(s); err != nil || v != ULID(id) {
+		t.Errorf("ParseULID, got %v (error %v), expected %v", v, err, id)
+	}
+	for _, s := range []string{"", "01ARYZ6S4CBCQ0VNSZ3J23S4A", "81ARYZ6S4CBCQ0VNSZ3J23S4AZ",
+		"01ARYZ6S4CBCQ0VNSZ3J23S4AU"} {
+		if v, err := DecodeCrockford128(s); err == nil {
+			t.Errorf("DecodeCrockford128(%q): expected error, got %v", s, v)
+		}
+	}
+	bad := s + string(crockfordCheck[(mod37(id)+1)%37])
+	if v, err := DecodeCrockford128Check(bad); err == nil {
+		t.Errorf("DecodeCrockford128Check(%q): expected error, got %v", bad, v)
+	}
+}
@@ -0,0 +1,61 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+)
+
+type dedup struct {
+	gen     Interface
+	retries int
+	mu      sync.Mutex
+	// recent holds the last issued IDs in a ring, oldest at next, indexed by seen.
+	recent []int64
+	next   int
+	seen   map[int64]struct{}
+}
+
+// NewDedup wraps gen so that none of the last size IDs issued is repeated: colliding
+// IDs are discarded and regenerated, up to retries times before failing with
+// ErrSuspectedRepeat. It is a safety net for random generators with few bits (e.g. a
+// Feistel or NanoID-like scheme truncated to fit a column); memory grows with size.
+// Its NewIDs method only accepts n=1. Safe for concurrent use.
+func NewDedup(gen Interface, size, retries int) (Interface, error) {
+	if size < 1 || retries < 0 {
+		return nil, fmt.Errorf("NewDedup(%d, %d): size must be positive and retries non-negative",
+			size, retries)
+	}
+	return &dedup{
+		gen:     gen,
+		retries: retries,
+		recent:  make([]int64, 0, size),
+		seen:    make(map[int64]struct{}, size),
+	}, nil
+}
+
+func (d *dedup) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(d, n)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for i := 0; i <= d.retries; i++ {
+		id, err := d.gen.NewIDs(1)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := d.seen[id]; ok {
+			continue
+		}
+		if len(d.recent) < cap(d.recent) {
+			d.recent = append(d.recent, id)
+		} else {
+			delete(d.seen, d.recent[d.next])
+			d.recent[d.next] = id
+			d.next = (d.next + 1) % len(d.recent)
+		}
+		d.seen[id] = struct{}{}
+		return id, nil
+	}
+	return 0, fmt.Errorf("%T.NewIDs(): repeated %d times: %w", d.gen, d.retries+1, ErrSuspectedRepeat)
+}
@@ -0,0 +1,55 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+)
+
+// cycle repeats ids forever.
+type cycle struct {
+	ids []int64
+	i   int
+}
+
+func (c *cycle) NewIDs(int64) (int64, error) {
+	id := c.ids[c.i%len(c.ids)]
+	c.i++
+	return id, nil
+}
+
+func TestDedup(t *testing.T) {
+	t.Parallel()
+	gen, err := NewDedup(&cycle{ids: []int64{1, 2, 1, 3, 2, 4, 5, 1, 5, 1}}, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		expected int64
+		err      error
+	}{
+		{1, nil},
+		{2, nil},
+		{3, nil}, // 1 is recent: skipped.
+		{4, nil}, // 2 is recent: skipped.
+		{5, nil},
+		{1, nil}, // 1 is no longer recent.
+		{0, ErrSuspectedRepeat},
+	}
+	for i, test := range tests {
+		v, err := gen.NewIDs(1)
+		if v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("TestDedup %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	if _, err := gen.NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestDedup: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+	if _, err := NewDedup(repeat{}, 0, 1); err == nil {
+		t.Errorf("TestDedup: expected error for size 0")
+	}
+	gen, _ = NewDedup(broken{errTest}, 1, 1)
+	if _, err := gen.NewIDs(1); !errors.Is(err, errTest) {
+		t.Errorf("TestDedup: got error %v, expected %v", err, errTest)
+	}
+}
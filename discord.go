@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// DiscordEpoch is the start of Discord's Snowflake timestamps (2015-01-01 00:00:00 UTC).
+var DiscordEpoch = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// NewDiscordSnowflake returns a Snowflake generator compatible with Discord's IDs:
+// SnowflakeLayout with Milliseconds since DiscordEpoch, and the nodeMask split into a
+// 5-bit worker and a 5-bit process ID. Both must be below 32, otherwise NewIDs fails.
+// opts are applied after the epoch. Safe for concurrent use.
+func NewDiscordSnowflake(worker, process int64, opts ...Option) Interface {
+	node := worker<<5 | process
+	if process < 0 || process >= 1<<5 {
+		// Make the node check fail instead of corrupting the worker.
+		node = -1
+	}
+	return SnowflakeLayout.NewSnowflake(node, append([]Option{WithEpoch(DiscordEpoch)}, opts...)...)
+}
+
+// DiscordSnowflake holds the fields of a Discord ID.
+type DiscordSnowflake struct {
+	ID              int64
+	Time            time.Time
+	Worker, Process int64
+	// Increment is the sequence number.
+	Increment int64
+}
+
+// ParseDiscordSnowflake parses a Discord ID, which Discord's API formats as a decimal
+// string.
+func ParseDiscordSnowflake(s string) (DiscordSnowflake, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return DiscordSnowflake{}, fmt.Errorf("ParseDiscordSnowflake(%q): invalid ID", s)
+	}
+	return DecomposeDiscordSnowflake(id), nil
+}
+
+// DecomposeDiscordSnowflake splits a Discord ID into its fields, with Time in UTC.
+func DecomposeDiscordSnowflake(id int64) DiscordSnowflake {
+	ts, node, seq := SnowflakeLayout.Decompose(id, WithEpoch(DiscordEpoch))
+	return DiscordSnowflake{ID: id, Time: ts.UTC(), Worker: node >> 5, Process: node & (1<<5 - 1), Increment: seq}
+}
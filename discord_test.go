@@ -0,0 +1,53 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDiscordSnowflake(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	clock := ClockFunc(func() int64 { return now.UnixNano() })
+	gen := NewDiscordSnowflake(3, 17, WithClock(clock))
+	id, err := gen.NewIDs(2)
+	if err != nil {
+		t.Fatalf("TestDiscordSnowflake: got error %v", err)
+	}
+	expected := DiscordSnowflake{ID: id, Time: now, Worker: 3, Process: 17, Increment: 1}
+	if d := DecomposeDiscordSnowflake(id); d != expected {
+		t.Errorf("TestDiscordSnowflake: got %+v, expected %+v", d, expected)
+	}
+
+	for _, gen := range []Interface{
+		NewDiscordSnowflake(32, 0, WithClock(clock)),
+		NewDiscordSnowflake(0, 32, WithClock(clock)),
+		NewDiscordSnowflake(0, -1, WithClock(clock)),
+	} {
+		if v, err := gen.NewIDs(1); !errors.Is(err, ErrOverflow) {
+			t.Errorf("TestDiscordSnowflake: got %v (error %v), expected %v", v, err, ErrOverflow)
+		}
+	}
+}
+
+func TestParseDiscordSnowflake(t *testing.T) {
+	t.Parallel()
+	// Example from Discord's API reference.
+	d, err := ParseDiscordSnowflake("175928847299117063")
+	expected := DiscordSnowflake{
+		ID:        175928847299117063,
+		Time:      time.Date(2016, 4, 30, 11, 18, 25, 796e6, time.UTC),
+		Worker:    1,
+		Process:   0,
+		Increment: 7,
+	}
+	if err != nil || d != expected {
+		t.Errorf("TestParseDiscordSnowflake: got %+v (error %v), expected %+v", d, err, expected)
+	}
+	for _, s := range []string{"", "x", "-1", "99999999999999999999"} {
+		if d, err := ParseDiscordSnowflake(s); err == nil {
+			t.Errorf("TestParseDiscordSnowflake(%q): got %+v, expected error", s, d)
+		}
+	}
+}
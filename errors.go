@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrClockMovedBack is returned by Snowflake-like generators when the clock goes back
+// in time (e.g. an NTP step or a VM migration), since reusing old timestamps could
+// produce duplicate IDs. See WithWaitOnClockRollback.
+var ErrClockMovedBack = errors.New("idgen: clock moved backwards")
+
+// ErrClockSkew is returned by Snowflake-like generators while their ClockMonitor reports
+// that the local clock is too far from the reference. See WithClockMonitor.
+var ErrClockSkew = errors.New("idgen: clock skew exceeds threshold")
+
+// ErrOverflow is wrapped by errors from generators whose IDs no longer fit in the bits
+// available to them (e.g. a Snowflake sequence exhausted within one timestamp).
+var ErrOverflow = errors.New("idgen: overflow")
+
+// ErrUnsupportedCount is wrapped by errors from generators asked for a batch size they
+// cannot provide (e.g. constants and timestamps only support count=1).
+var ErrUnsupportedCount = errors.New("idgen: unsupported count")
+
+// ErrExhausted is wrapped by errors from sequential generators that reached their
+// ceiling (math.MaxInt64 unless set), instead of wrapping around and repeating IDs.
+var ErrExhausted = errors.New("idgen: IDs exhausted")
+
+// ErrClosed is returned by generators used after Close.
+var ErrClosed = errors.New("idgen: generator closed")
+
+// ErrSuspectedRepeat is returned by generators wrapped with NewBloomChecked when an ID
+// was probably issued before.
+var ErrSuspectedRepeat = errors.New("idgen: suspected repeated ID")
+
+// ErrRateLimited is returned by RateLimited generators when issuing IDs would exceed
+// their rate.
+var ErrRateLimited = errors.New("idgen: rate limited")
+
+// ErrReservationDone is returned when committing or releasing a Reservation twice.
+var ErrReservationDone = errors.New("idgen: reservation already committed or released")
+
+// ErrWouldBlock is returned by generators that would have to wait longer than allowed by
+// WithMaxWait, e.g. for the clock to catch up.
+var ErrWouldBlock = errors.New("idgen: would block")
+
+// ErrDeadline is returned by NewIDsContext when the context deadline passes (or would
+// pass) while waiting. It wraps context.DeadlineExceeded.
+var ErrDeadline = fmt.Errorf("idgen: wait deadline exceeded: %w", context.DeadlineExceeded)
+
+// Error describes a failed NewIDs call, so programs can inspect and alert on failures
+// without parsing messages. It wraps one of the sentinel errors above.
+type Error struct {
+	// Generator is the Go type of the failing generator, e.g. "*idgen.sequential".
+	Generator string
+	// Count is the n passed to NewIDs.
+	Count int64
+	// Value is the offending value, e.g. the ID that did not fit.
+	Value int64
+	// Bit is the lowest bit outside the allowed range, or -1 if not an overflow (or a
+	// range overflow, see NewRangeChecker).
+	Bit int
+	// Err is the underlying sentinel error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Bit >= 0 {
+		return fmt.Sprintf("%s.NewIDs(%d): %d overflows at bit %d: %v",
+			e.Generator, e.Count, e.Value, e.Bit, e.Err)
+	}
+	return fmt.Sprintf("%s.NewIDs(%d): %v", e.Generator, e.Count, e.Err)
+}
+
+// Unwrap returns the underlying sentinel error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,120 @@
+// Package etcdnode allocates Snowflake nodeMasks using etcd leases.
+package etcdnode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Allocator claims a nodeMask by creating the key prefix+nodeMask attached to a lease,
+// which is kept alive in the background. If the process dies, the lease expires after
+// its TTL and the nodeMask becomes free again. Safe for concurrent use.
+type Allocator struct {
+	client   *clientv3.Client
+	prefix   string
+	nodeBits byte
+	ttl      int64
+
+	mu     sync.Mutex
+	lease  clientv3.LeaseID
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	_ idgen.NodeAllocator = (*Allocator)(nil)
+	_ io.Closer           = (*Allocator)(nil)
+)
+
+// New returns an Allocator for nodeMasks of nodeBits (e.g. idgen.SnowflakeLayout.NodeBits),
+// using keys under prefix (e.g. "/idgen/myservice/"). The ttl is rounded to Seconds
+// (at least one).
+func New(client *clientv3.Client, prefix string, nodeBits byte, ttl time.Duration) *Allocator {
+	secs := int64(ttl / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return &Allocator{client: client, prefix: prefix, nodeBits: nodeBits, ttl: secs}
+}
+
+// Acquire implements idgen.NodeAllocator, claiming the lowest free nodeMask.
+func (a *Allocator) Acquire(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		return 0, errors.New("etcdnode: already acquired")
+	}
+
+	grant, err := a.client.Grant(ctx, a.ttl)
+	if err != nil {
+		return 0, err
+	}
+	host, _ := os.Hostname()
+	owner := fmt.Sprintf("%s/%d", host, os.Getpid())
+	for node := int64(0); node < 1<<a.nodeBits; node++ {
+		key := a.prefix + strconv.FormatInt(node, 10)
+		resp, err := a.client.Txn(ctx).
+			If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+			Then(clientv3.OpPut(key, owner, clientv3.WithLease(grant.ID))).
+			Commit()
+		if err != nil {
+			a.client.Revoke(context.Background(), grant.ID)
+			return 0, err
+		}
+		if !resp.Succeeded {
+			continue
+		}
+
+		keepCtx, cancel := context.WithCancel(context.Background())
+		ch, err := a.client.KeepAlive(keepCtx, grant.ID)
+		if err != nil {
+			cancel()
+			a.client.Revoke(context.Background(), grant.ID)
+			return 0, err
+		}
+		a.lease, a.cancel, a.done = grant.ID, cancel, make(chan struct{})
+		go func(done chan struct{}) {
+			// The channel is closed when the lease can no longer be renewed.
+			for range ch {
+			}
+			close(done)
+		}(a.done)
+		return node, nil
+	}
+	a.client.Revoke(context.Background(), grant.ID)
+	return 0, idgen.ErrNoFreeNode
+}
+
+// Release implements idgen.NodeAllocator, revoking the lease (which deletes the key).
+func (a *Allocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel == nil {
+		return nil
+	}
+	a.cancel()
+	a.cancel = nil
+	_, err := a.client.Revoke(ctx, a.lease)
+	return err
+}
+
+// Done implements idgen.NodeAllocator. It returns nil before Acquire.
+func (a *Allocator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done
+}
+
+// Close implements io.Closer, releasing the nodeMask (see Release).
+func (a *Allocator) Close() error {
+	return a.Release(context.Background())
+}
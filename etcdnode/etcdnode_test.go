@@ -0,0 +1,59 @@
+package etcdnode
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// TestAllocator requires an etcd server, e.g. ETCD_ENDPOINTS=localhost:2379.
+func TestAllocator(t *testing.T) {
+	endpoints := os.Getenv("ETCD_ENDPOINTS")
+	if endpoints == "" {
+		t.Skip("ETCD_ENDPOINTS not set")
+	}
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   strings.Split(endpoints, ","),
+		DialTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	ctx := context.Background()
+	prefix := "/idgen-test/" + time.Now().Format(time.RFC3339Nano) + "/"
+
+	a, b, c := New(client, prefix, 1, time.Second), New(client, prefix, 1, time.Second),
+		New(client, prefix, 1, time.Second)
+	nodeA, errA := a.Acquire(ctx)
+	nodeB, errB := b.Acquire(ctx)
+	switch {
+	case errA != nil || errB != nil:
+		t.Fatalf("got errors %v, %v", errA, errB)
+	case nodeA == nodeB:
+		t.Fatalf("got same node %v", nodeA)
+	}
+	if node, err := c.Acquire(ctx); err != idgen.ErrNoFreeNode {
+		t.Errorf("got %v (error %v), expected error %v", node, err, idgen.ErrNoFreeNode)
+	}
+	// Longer than the TTL: the keep-alive must hold the claim.
+	time.Sleep(3 * time.Second)
+	select {
+	case <-a.Done():
+		t.Errorf("claim lost")
+	default:
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Errorf("got error %q", err)
+	}
+	if node, err := c.Acquire(ctx); err != nil || node != nodeA {
+		t.Errorf("got %v (error %v), expected %v", node, err, nodeA)
+	}
+	b.Release(ctx)
+	c.Release(ctx)
+}
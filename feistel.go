@@ -0,0 +1,85 @@
+package idgen
+
+// feistelRounds is the number of rounds of the Feistel network (enough for mixing,
+// obfuscation is the goal, not cryptographic security).
+const feistelRounds = 6
+
+// Feistel wraps an ID generator, permuting each ID with a keyed Feistel network. The
+// result looks random but is unique as long as the wrapped IDs are (the mapping is 1:1,
+// and Inverse recovers the original). The sign is preserved, so non-negative IDs stay
+// non-negative. Its NewIDs method only accepts n=1, since permuted IDs are not
+// contiguous. Safe for concurrent use if gen is.
+type Feistel struct {
+	gen  Interface
+	keys [feistelRounds]uint64
+}
+
+// NewFeistel returns a Feistel wrapper around gen, with round keys derived from key.
+func NewFeistel(gen Interface, key uint64) *Feistel {
+	f := &Feistel{gen: gen}
+	for i := range f.keys {
+		key += 0x9e3779b97f4a7c15
+		f.keys[i] = mix64(key)
+	}
+	return f
+}
+
+// NewIDs implements Interface.
+func (f *Feistel) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(f, n)
+	}
+	v, err := f.gen.NewIDs(1)
+	if err != nil {
+		return 0, err
+	}
+	return f.Permute(v), nil
+}
+
+// Permute maps v to its obfuscated form.
+func (f *Feistel) Permute(v int64) int64 {
+	return f.walk(v, f.encrypt)
+}
+
+// Inverse maps an obfuscated value back to the original, so Inverse(Permute(v)) == v.
+func (f *Feistel) Inverse(v int64) int64 {
+	return f.walk(v, f.decrypt)
+}
+
+// walk applies the 64-bit permutation until the sign bit matches v's (cycle-walking), which
+// is a permutation restricted to v's half of the domain.
+func (f *Feistel) walk(v int64, perm func(uint64) uint64) int64 {
+	sign := uint64(v) >> 63
+	x := perm(uint64(v))
+	for x>>63 != sign {
+		x = perm(x)
+	}
+	return int64(x)
+}
+
+func (f *Feistel) encrypt(x uint64) uint64 {
+	l, r := uint32(x>>32), uint32(x)
+	for _, k := range f.keys {
+		l, r = r, l^feistelRound(r, k)
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+func (f *Feistel) decrypt(x uint64) uint64 {
+	l, r := uint32(x>>32), uint32(x)
+	for i := len(f.keys) - 1; i >= 0; i-- {
+		l, r = r^feistelRound(l, f.keys[i]), l
+	}
+	return uint64(l)<<32 | uint64(r)
+}
+
+func feistelRound(r uint32, k uint64) uint32 {
+	return uint32(mix64(uint64(r) ^ k))
+}
+
+// mix64 is the SplitMix64 finalizer.
+func mix64(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return z ^ z>>31
+}
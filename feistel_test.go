@@ -0,0 +1,44 @@
+package idgen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFeistel(t *testing.T) {
+	f := NewFeistel(NewSequential(), 42)
+	seen := make(map[int64]bool)
+	for i := int64(1); i <= 10000; i++ {
+		v, err := f.NewIDs(1)
+		switch {
+		case err != nil:
+			t.Fatalf("%d: got error %q", i, err)
+		case v < 0:
+			t.Fatalf("%d: got negative %d", i, v)
+		case seen[v]:
+			t.Fatalf("%d: duplicate %d", i, v)
+		case v == i:
+			t.Errorf("%d: not permuted", i)
+		case f.Inverse(v) != i:
+			t.Errorf("%d: inverse, got %d", i, f.Inverse(v))
+		}
+		seen[v] = true
+	}
+
+	for _, v := range []int64{0, -1, math.MinInt64, math.MaxInt64, -12345} {
+		p := f.Permute(v)
+		if (p < 0) != (v < 0) || f.Inverse(p) != v {
+			t.Errorf("Permute(%d) = %d, Inverse = %d", v, p, f.Inverse(p))
+		}
+	}
+	if p, q := f.Permute(1), NewFeistel(nil, 43).Permute(1); p == q {
+		t.Errorf("different keys, got same %d", p)
+	}
+
+	if v, err := f.NewIDs(2); err == nil {
+		t.Errorf("expected error for n=2, got %d", v)
+	}
+	if _, err := NewFeistel(broken{errTest}, 1).NewIDs(1); err != errTest {
+		t.Errorf("got error %v, expected %v", err, errTest)
+	}
+}
@@ -0,0 +1,108 @@
+// Package filenode allocates Snowflake nodeMasks among processes of the same machine,
+// using lock files in a shared directory.
+package filenode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+)
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("filenode: locked")
+
+// Allocator claims a nodeMask by holding an exclusive lock (flock) on the file
+// dir/nodeMask.lock. The operating system releases the lock when the process exits, so
+// the nodeMask becomes free again even after a crash. The files are not removed. Only
+// processes on the same machine are coordinated (network file systems may not support
+// locks), so the nodeMask range must be partitioned among machines by other means.
+// Safe for concurrent use.
+type Allocator struct {
+	dir      string
+	nodeBits byte
+
+	mu   sync.Mutex
+	file *os.File
+	done chan struct{}
+}
+
+var (
+	_ idgen.NodeAllocator = (*Allocator)(nil)
+	_ io.Closer           = (*Allocator)(nil)
+)
+
+// New returns an Allocator for nodeMasks of nodeBits (e.g.
+// idgen.SnowflakeLayout.NodeBits), using lock files in dir, which is created if needed.
+func New(dir string, nodeBits byte) *Allocator {
+	return &Allocator{dir: dir, nodeBits: nodeBits}
+}
+
+// Acquire implements idgen.NodeAllocator, claiming the lowest free nodeMask.
+func (a *Allocator) Acquire(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file != nil {
+		return 0, errors.New("filenode: already acquired")
+	}
+	if err := os.MkdirAll(a.dir, 0o755); err != nil {
+		return 0, err
+	}
+	for node := int64(0); node < 1<<a.nodeBits; node++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		name := filepath.Join(a.dir, strconv.FormatInt(node, 10)+".lock")
+		f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return 0, err
+		}
+		switch err := tryLock(f); err {
+		case nil:
+			// The owner is informative only.
+			f.Truncate(0)
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			a.file, a.done = f, make(chan struct{})
+			return node, nil
+		case errLocked:
+			f.Close()
+		default:
+			f.Close()
+			return 0, err
+		}
+	}
+	return 0, idgen.ErrNoFreeNode
+}
+
+// Release implements idgen.NodeAllocator, unlocking the file.
+func (a *Allocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.file == nil {
+		return nil
+	}
+	// Closing the file releases the lock.
+	err := a.file.Close()
+	a.file = nil
+	close(a.done)
+	return err
+}
+
+// Done implements idgen.NodeAllocator. Locks are never lost while the process runs, so
+// it is only closed by Release. It returns nil before Acquire.
+func (a *Allocator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done
+}
+
+// Close implements io.Closer, releasing the nodeMask (see Release).
+func (a *Allocator) Close() error {
+	return a.Release(context.Background())
+}
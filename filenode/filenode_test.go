@@ -0,0 +1,46 @@
+package filenode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestAllocator(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	// Locks are held per open file, so allocators in one process exclude each other.
+	a, b, c := New(dir, 1), New(dir, 1), New(dir, 1)
+	nodeA, errA := a.Acquire(ctx)
+	nodeB, errB := b.Acquire(ctx)
+	switch {
+	case errA != nil || errB != nil:
+		t.Fatalf("got errors %v, %v", errA, errB)
+	case nodeA != 0 || nodeB != 1:
+		t.Fatalf("got nodes %v, %v, expected 0, 1", nodeA, nodeB)
+	}
+	if node, err := a.Acquire(ctx); err == nil {
+		t.Errorf("expected error acquiring twice, got %v", node)
+	}
+	if node, err := c.Acquire(ctx); err != idgen.ErrNoFreeNode {
+		t.Errorf("got %v (error %v), expected error %v", node, err, idgen.ErrNoFreeNode)
+	}
+	select {
+	case <-a.Done():
+		t.Errorf("Done closed before Release")
+	default:
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Errorf("got error %q", err)
+	}
+	<-a.Done()
+	if node, err := c.Acquire(ctx); err != nil || node != nodeA {
+		t.Errorf("got %v (error %v), expected %v", node, err, nodeA)
+	}
+	for _, alloc := range []*Allocator{a, b, c} {
+		if err := alloc.Release(ctx); err != nil {
+			t.Errorf("got error %q", err)
+		}
+	}
+}
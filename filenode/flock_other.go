@@ -0,0 +1,12 @@
+//go:build !unix
+
+package filenode
+
+import (
+	"errors"
+	"os"
+)
+
+func tryLock(f *os.File) error {
+	return errors.New("filenode: file locks are not supported on this platform")
+}
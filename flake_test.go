@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFlake(t *testing.T) {
+	f := Flake{0, 0, 0x01, 0x8f, 0x4c, 0x1e, 0x2a, 0x40, 0x02, 0x42, 0xac, 0x11, 0x00, 0x02, 0x01, 0x03}
+	s := "0000018f4c1e2a400242ac1100020103"
+	switch {
+	case f.String() != s:
+		t.Errorf("repr, got %s, expected %s", f.String(), s)
+	case !f.Time().Equal(time.UnixMilli(0x018f4c1e2a40)):
+		t.Errorf("time, got %v", f.Time())
+	case f.Worker() != 0x0242ac110002:
+		t.Errorf("worker, got %x", f.Worker())
+	case f.Sequence() != 0x0103:
+		t.Errorf("sequence, got %x", f.Sequence())
+	}
+	var v Flake
+	if err := v.UnmarshalText([]byte(s)); err != nil || v != f {
+		t.Errorf("parse, got %v (error %v), expected %v", v, err, f)
+	}
+	for _, s := range []string{"", s[1:], s[1:] + "x"} {
+		if v, err := ParseFlake(s); err == nil {
+			t.Errorf("%q: got %v, expected error", s, v)
+		}
+	}
+}
+
+func TestFlakeGenerator(t *testing.T) {
+	g, err := NewFlakeGenerator(0x0242ac110002)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		ms, seq int64
+		err     error
+	}{
+		{10, 0, nil},
+		{10, 1, nil},
+		{11, 0, nil},
+		{9, 0, ErrClockMovedBack},
+		{11, 1, nil},
+	}
+	var last Flake
+	for i, test := range tests {
+		f, err := g.next(test.ms)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%d: got error %v, expected %v", i, err, test.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if f.Time().UnixMilli() != test.ms || f.Sequence() != test.seq || f.Worker() != 0x0242ac110002 {
+			t.Errorf("%d: got %v, expected %d/%d", i, f, test.ms, test.seq)
+		}
+		if f.String() <= last.String() {
+			t.Errorf("%d: got %v after %v", i, f, last)
+		}
+		last = f
+	}
+	g.seq = 1<<16 - 1
+	if _, err := g.next(11); !errors.Is(err, ErrOverflow) {
+		t.Errorf("got error %v, expected %v", err, ErrOverflow)
+	}
+	if f, err := g.New(); err != nil || time.Since(f.Time()) > time.Minute {
+		t.Errorf("New, got %v (error %v)", f, err)
+	}
+	if _, err := NewFlakeGenerator(1 << 48); err == nil {
+		t.Errorf("expected error for a 49-bit worker")
+	}
+	if _, err := NewFlakeGeneratorMAC(); err != nil {
+		t.Errorf("NewFlakeGeneratorMAC, got error %v", err)
+	}
+}
@@ -0,0 +1,177 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// HashidsAlphabet is the default Hashids alphabet.
+const HashidsAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+const hashidsSeps = "cfhistuCFHISTU"
+
+// Hashids wraps an ID generator, encoding each ID as a short string that does not reveal
+// the sequence, following the Hashids algorithm (https://hashids.org), so IDs are
+// compatible with other implementations configured with the same salt. Obfuscation is
+// not encryption: it only deters enumeration of IDs.
+// Safe for concurrent use if gen is.
+type Hashids struct {
+	gen                    Interface
+	salt                   []byte
+	alphabet, seps, guards []byte
+}
+
+// NewHashids returns a Hashids encoder around gen, using HashidsAlphabet.
+func NewHashids(gen Interface, salt string) *Hashids {
+	h, _ := NewHashidsAlphabet(gen, salt, HashidsAlphabet)
+	return h
+}
+
+// NewHashidsAlphabet is like NewHashids, with a custom alphabet of at least 16 unique
+// characters (no spaces).
+func NewHashidsAlphabet(gen Interface, salt, alphabet string) (*Hashids, error) {
+	h := &Hashids{gen: gen, salt: []byte(salt)}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		switch {
+		case c == ' ':
+			return nil, fmt.Errorf("NewHashidsAlphabet(%q): spaces are not allowed", alphabet)
+		case strings.IndexByte(alphabet[:i], c) >= 0:
+			return nil, fmt.Errorf("NewHashidsAlphabet(%q): duplicate %q", alphabet, c)
+		case strings.IndexByte(hashidsSeps, c) >= 0:
+			h.seps = append(h.seps, c)
+		default:
+			h.alphabet = append(h.alphabet, c)
+		}
+	}
+	if len(alphabet) < 16 {
+		return nil, fmt.Errorf("NewHashidsAlphabet(%q): at least 16 characters required", alphabet)
+	}
+
+	hashidsShuffle(h.seps, h.salt)
+	if len(h.seps) == 0 || float64(len(h.alphabet))/float64(len(h.seps)) > 3.5 {
+		n := int(math.Ceil(float64(len(h.alphabet)) / 3.5))
+		if n == 1 {
+			n = 2
+		}
+		if n > len(h.seps) {
+			diff := n - len(h.seps)
+			h.seps = append(h.seps, h.alphabet[:diff]...)
+			h.alphabet = h.alphabet[diff:]
+		} else {
+			h.seps = h.seps[:n]
+		}
+	}
+	hashidsShuffle(h.alphabet, h.salt)
+	guards := int(math.Ceil(float64(len(h.alphabet)) / 12))
+	if len(h.alphabet) < 3 {
+		h.guards, h.seps = h.seps[:guards], h.seps[guards:]
+	} else {
+		h.guards, h.alphabet = h.alphabet[:guards], h.alphabet[guards:]
+	}
+	return h, nil
+}
+
+// NewID generates an ID and returns its encoded form.
+func (h *Hashids) NewID() (string, error) {
+	id, err := h.gen.NewIDs(1)
+	if err != nil {
+		return "", err
+	}
+	return h.Encode(id)
+}
+
+// Encode returns the string form of id, which must not be negative.
+func (h *Hashids) Encode(id int64) (string, error) {
+	if id < 0 {
+		return "", fmt.Errorf("Hashids.Encode(%d): negative IDs are not supported", id)
+	}
+	alphabet := append([]byte(nil), h.alphabet...)
+	lottery := alphabet[id%100%int64(len(alphabet))]
+	buf := h.shuffleBuffer(lottery, alphabet)
+	hashidsShuffle(alphabet, buf)
+	return string(lottery) + string(hashidsHash(id, alphabet)), nil
+}
+
+// Decode returns the ID encoded by Encode.
+func (h *Hashids) Decode(s string) (int64, error) {
+	errInvalid := fmt.Errorf("Hashids.Decode(%q): invalid", s)
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r < 128 && strings.IndexByte(string(h.guards), byte(r)) >= 0
+	})
+	switch len(parts) {
+	case 0:
+		return 0, errInvalid
+	case 2, 3:
+		parts[0] = parts[1]
+	}
+	part := parts[0]
+	if len(part) < 2 || strings.ContainsAny(part, string(h.seps)) {
+		return 0, errInvalid
+	}
+	alphabet := append([]byte(nil), h.alphabet...)
+	buf := h.shuffleBuffer(part[0], alphabet)
+	hashidsShuffle(alphabet, buf)
+	id, err := hashidsUnhash(part[1:], alphabet)
+	if err != nil {
+		return 0, errInvalid
+	}
+	// Reject strings that are not produced by Encode (e.g. with a wrong lottery).
+	if check, _ := h.Encode(id); check != s {
+		return 0, errInvalid
+	}
+	return id, nil
+}
+
+func (h *Hashids) shuffleBuffer(lottery byte, alphabet []byte) []byte {
+	buf := make([]byte, 0, 1+len(h.salt)+len(alphabet))
+	buf = append(buf, lottery)
+	buf = append(buf, h.salt...)
+	buf = append(buf, alphabet...)
+	return buf[:len(alphabet)]
+}
+
+// hashidsShuffle permutes alphabet in place, deterministically according to salt.
+func hashidsShuffle(alphabet, salt []byte) {
+	if len(salt) == 0 {
+		return
+	}
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+}
+
+func hashidsHash(v int64, alphabet []byte) []byte {
+	var digits []byte
+	for {
+		digits = append(digits, alphabet[v%int64(len(alphabet))])
+		if v /= int64(len(alphabet)); v == 0 {
+			break
+		}
+	}
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return digits
+}
+
+func hashidsUnhash(s string, alphabet []byte) (int64, error) {
+	var v int64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(string(alphabet), s[i])
+		if d < 0 {
+			return 0, errors.New("invalid character")
+		}
+		if v > (math.MaxInt64-int64(d))/int64(len(alphabet)) {
+			return 0, errors.New("overflow")
+		}
+		v = v*int64(len(alphabet)) + int64(d)
+	}
+	return v, nil
+}
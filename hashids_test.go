@@ -0,0 +1,61 @@
+package idgen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHashids(t *testing.T) {
+	h := NewHashids(NewSequential(), "this is my salt")
+	// From the reference implementation's documentation.
+	if s, err := h.Encode(12345); err != nil || s != "NkK9" {
+		t.Errorf("Encode(12345): got %q (error %v), expected %q", s, err, "NkK9")
+	}
+	for _, id := range []int64{0, 1, 99, 100, 12345, math.MaxInt64} {
+		s, err := h.Encode(id)
+		if err != nil {
+			t.Errorf("Encode(%d): got error %q", id, err)
+			continue
+		}
+		if v, err := h.Decode(s); err != nil || v != id {
+			t.Errorf("Decode(%q): got %d (error %v), expected %d", s, v, err, id)
+		}
+	}
+	if s, err := h.Encode(-1); err == nil {
+		t.Errorf("Encode(-1): expected error, got %q", s)
+	}
+	for _, s := range []string{"", "N", "NkK8", "Nk K9", "NkKc"} {
+		if v, err := h.Decode(s); err == nil {
+			t.Errorf("Decode(%q): expected error, got %d", s, v)
+		}
+	}
+
+	first, err1 := h.NewID()
+	second, err2 := h.NewID()
+	a, _ := h.Decode(first)
+	b, _ := h.Decode(second)
+	if err1 != nil || err2 != nil || a != 1 || b != 2 {
+		t.Errorf("NewID: got %q, %q (errors %v, %v)", first, second, err1, err2)
+	}
+	if _, err := NewHashids(broken{errTest}, "").NewID(); err != errTest {
+		t.Errorf("NewID: got error %v, expected %v", err, errTest)
+	}
+}
+
+func TestHashidsAlphabet(t *testing.T) {
+	for _, alphabet := range []string{"abc", "abcdefghijklmnoa", "abcdefghijklmno "} {
+		if _, err := NewHashidsAlphabet(NewSequential(), "", alphabet); err == nil {
+			t.Errorf("NewHashidsAlphabet(%q): expected error", alphabet)
+		}
+	}
+	h, err := NewHashidsAlphabet(NewSequential(), "salt", "0123456789abcdef")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	for id := int64(0); id < 1000; id++ {
+		s, err := h.Encode(id)
+		if v, err2 := h.Decode(s); err != nil || err2 != nil || v != id {
+			t.Errorf("%d: got %q -> %d (errors %v, %v)", id, s, v, err, err2)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package idgen
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ID is an int64 ID that formats as a decimal string in text and JSON, so it survives
+// JavaScript clients (which lose precision above 2^53), and maps to integer columns in
+// SQL. Generate them with NewIDGen or Typed[ID].
+type ID int64
+
+// NewIDGen returns a generator of IDs.
+func NewIDGen(gen Interface) Gen[ID] {
+	return Typed[ID](gen)
+}
+
+// NewSnowflakeIDGen returns a Snowflake generator of IDs, see NewSnowflake.
+func NewSnowflakeIDGen(nodeMask int64, opts ...Option) Gen[ID] {
+	return NewIDGen(NewSnowflake(nodeMask, opts...))
+}
+
+// ParseID decodes the representation produced by ID.String.
+func ParseID(s string) (ID, error) {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ParseID(%q): invalid format", s)
+	}
+	return ID(v), nil
+}
+
+// Int64 returns id as an int64.
+func (id ID) Int64() int64 {
+	return int64(id)
+}
+
+// String returns id in decimal.
+func (id ID) String() string {
+	return strconv.FormatInt(int64(id), 10)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (id ID) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (id *ID) UnmarshalText(b []byte) error {
+	v, err := ParseID(string(b))
+	if err != nil {
+		return err
+	}
+	*id = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, as a string.
+func (id ID) MarshalJSON() ([]byte, error) {
+	b := append([]byte{'"'}, strconv.FormatInt(int64(id), 10)...)
+	return append(b, '"'), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting strings and numbers. null leaves
+// id unchanged, as usual in encoding/json.
+func (id *ID) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return fmt.Errorf("ID.UnmarshalJSON: %v", err)
+		}
+		b = []byte(s)
+	}
+	return id.UnmarshalText(b)
+}
+
+// Scan implements sql.Scanner. Columns can be either integers or text in decimal. NULL
+// is scanned as 0.
+func (id *ID) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*id = 0
+	case int64:
+		*id = ID(v)
+	case string:
+		*id, err = ParseID(v)
+	case []byte:
+		*id, err = ParseID(string(v))
+	default:
+		err = fmt.Errorf("ID.Scan: unsupported type %T", src)
+	}
+	return err
+}
+
+// Value implements driver.Valuer, as an int64.
+func (id ID) Value() (driver.Value, error) {
+	return int64(id), nil
+}
@@ -0,0 +1,72 @@
+package idgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestID(t *testing.T) {
+	t.Parallel()
+	type doc struct {
+		ID   ID         `json:"id"`
+		Refs map[ID]int `json:"refs"`
+	}
+	gen := NewIDGen(NewConstant(1 << 60))
+	id, err := gen.New()
+	if err != nil || id.Int64() != 1<<60 || id.String() != "1152921504606846976" {
+		t.Fatalf("TestID: got %v (error %v)", id, err)
+	}
+	if s := fmt.Sprint(id); s != id.String() {
+		t.Errorf("TestID: got %q, expected %q", s, id.String())
+	}
+	b, err := json.Marshal(doc{id, map[ID]int{id: 1}})
+	expected := `{"id":"1152921504606846976","refs":{"1152921504606846976":1}}`
+	if err != nil || string(b) != expected {
+		t.Errorf("TestID: got %s (error %v), expected %s", b, err, expected)
+	}
+	for _, s := range []string{
+		expected,
+		`{"id":1152921504606846976}`,
+		`{"id":"\u0031152921504606846976"}`,
+	} {
+		var d doc
+		if err := json.Unmarshal([]byte(s), &d); err != nil || d.ID != id {
+			t.Errorf("TestID %s: got %v (error %v), expected %v", s, d.ID, err, id)
+		}
+	}
+	// null leaves the ID unchanged.
+	d := doc{ID: id}
+	if err := json.Unmarshal([]byte(`{"id":null}`), &d); err != nil || d.ID != id {
+		t.Errorf("TestID: got %v (error %v) for null, expected %v", d.ID, err, id)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"x"}`), &d); err == nil {
+		t.Errorf("TestID: expected error")
+	}
+}
+
+func TestIDScan(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		src      interface{}
+		expected ID
+		ok       bool
+	}{
+		{nil, 0, true},
+		{int64(-3), -3, true},
+		{"42", 42, true},
+		{[]byte("43"), 43, true},
+		{"x", 0, false},
+		{1.5, 0, false},
+	}
+	for i, test := range tests {
+		id := ID(7)
+		err := id.Scan(test.src)
+		if (err == nil) != test.ok || (test.ok && id != test.expected) {
+			t.Errorf("TestIDScan %d: got %v (error %v), expected %v", i, id, err, test.expected)
+		}
+	}
+	if v, err := ID(5).Value(); err != nil || v != int64(5) {
+		t.Errorf("TestIDScan: got %v (error %v), expected 5", v, err)
+	}
+}
@@ -2,7 +2,10 @@
 package idgen
 
 import (
+	"context"
 	"fmt"
+	"math"
+	"math/bits"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,55 +24,151 @@ type (
 
 // NewSnowflake returns an ID generator that follows Twitter's Snowflake algorithm.
 // It can generate up to 4096 IDs per millisecond (so it tries to avoid clashes if possible),
-// and supports up to 1024 generating nodes, up until year 2038. ID's Leading bit is always 0
-// so the returned ID is never negative (i.e, 63 of 64 bits are significative).
+// and supports up to 1024 generating nodes, up until year 2038 (or 69 years after the
+// epoch given by WithEpoch). ID's Leading bit is always 0 so the returned ID is never
+// negative (i.e, 63 of 64 bits are significative).
 // Safe for concurrent use.
-func NewSnowflake(nodeMask int64) Interface {
+func NewSnowflake(nodeMask int64, opts ...Option) Interface {
+	return SnowflakeLayout.NewSnowflake(nodeMask, opts...)
+}
+
+// NewSnowflakeChecked is like NewSnowflake, validating the configuration up-front (see
+// Layout.Validate).
+func NewSnowflakeChecked(nodeMask int64, opts ...Option) (Interface, error) {
+	return SnowflakeLayout.NewSnowflakeChecked(nodeMask, opts...)
+}
+
+// DecomposeSnowflake splits an ID generated by NewSnowflake into its timestamp, nodeMask
+// and sequence number. The options must match the ones used for generation.
+func DecomposeSnowflake(id int64, opts ...Option) (ts time.Time, node int64, seq int64) {
+	return SnowflakeLayout.Decompose(id, opts...)
+}
+
+// SonyflakeEpoch is the start of Sonyflake's timestamps (2014-09-01 00:00:00 UTC).
+var SonyflakeEpoch = time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)
+
+// NewSonyflake returns an ID generator that follows Sony's Sonyflake algorithm: a 39-bit
+// timestamp (10 Milliseconds since SonyflakeEpoch), an 8-bit sequence and a 16-bit
+// machine ID. It supports more nodes than Snowflake, but only 256 IDs per 10
+// Milliseconds for each, up until year 2188. Like Snowflake, ID's leading bit is always 0.
+// Safe for concurrent use.
+func NewSonyflake(machineID uint16) Interface {
 	seq := &sequential{}
 	return &snowflake{
-		// Needed to reset when a new timestamp is entered.
-		sequential: seq,
-		// Least significant bits: only one that accepts counter > 1.
-		seqChecker: NewOverflowChecker(12, seq),
-		// TODO: does not check nodeMask overflow up-front.
-		constant: shifted{
-			gen:  NewOverflowChecker(10, constant(nodeMask)),
-			bits: 12,
-		},
+		lastTimestamp: -1,
+		sequential:    seq,
+		seqChecker:    NewOverflowChecker(8, seq),
+		seqBits:       16,
+		seqSize:       1 << 8,
+		constant:      constant(machineID),
 		tstamp: shifted{
-			gen:  NewOverflowChecker(41, NewTimestamp()),
-			bits: 22,
+			gen: NewOverflowChecker(39, tstamp{
+				epoch: SonyflakeEpoch.UnixNano(),
+				unit:  int64(10 * time.Millisecond),
+				clock: SystemClock,
+			}),
+			bits: 24,
 		},
 	}
 }
 
 // NewSequential returns an ID generator with reproducible results, so it is suitable for
-// tests.
+// tests. It fails with ErrExhausted rather than wrapping around past math.MaxInt64.
 func NewSequential() Interface {
 	return &sequential{}
 }
 
+// NewSequentialFrom returns an ID generator like NewSequential whose first ID is start.
+// It is useful for migrations, to continue after the highest ID already in use (pass it
+// plus one). From math.MinInt64, it issues at most 2^64-1 IDs (see NewNegSequentialRange).
+func NewSequentialFrom(start int64) Interface {
+	if start == math.MinInt64 {
+		// start-1 does not fit in the counter.
+		return newStrided(start, 1, math.MaxUint64)
+	}
+	return &sequential{value: start - 1}
+}
+
+// NewBoundedSequential returns an ID generator like NewSequentialFrom which fails with
+// ErrExhausted instead of issuing IDs past ceiling, e.g. to keep within a column type or
+// below IDs reserved for something else.
+func NewBoundedSequential(start, ceiling int64) (Interface, error) {
+	if start > ceiling {
+		return nil, fmt.Errorf("NewBoundedSequential(%d, %d): start must not exceed ceiling",
+			start, ceiling)
+	}
+	if start == math.MinInt64 {
+		return newStrided(start, 1, uint64(ceiling-start)), nil
+	}
+	return &sequential{value: start - 1, ceiling: ceiling, bounded: true}, nil
+}
+
 // NewNegSequential returns an ID generator with reproducible results which starts from
 // the lowest possible int64. It is useful for migrations, so when the system becomes
 // active and a concurrent generator is used, clashes are avoided implicitly
-// (since the following generator would only produce positive IDs).
+// (since the following generator would only produce positive IDs). See
+// NewNegSequentialRange to give each migration a bounded range.
 func NewNegSequential() Interface {
 	return &sequential{value: int64(-(1 << 63))}
 }
 
-// NewOverflowChecker wraps an ID generator to check for overflows.
+// NewOverflowChecker wraps an ID generator to check for overflows. Negative IDs always
+// overflow (at bit 63), see NewRangeChecker for generators issuing them.
 func NewOverflowChecker(allowedBits byte, gen Interface) Interface {
 	return overflowChecker{
 		gen:          gen,
+		name:         fmt.Sprintf("%T", gen),
 		overflowBits: ^(1<<allowedBits - 1),
 	}
 }
 
+// NewRangeChecker wraps an ID generator to fail with ErrOverflow when it issues IDs
+// outside min to max, e.g. [-1<<(b-1), 1<<(b-1)-1] for a signed b-bit field or the range
+// given to a NegSequential. Batches are assumed contiguous (like in NewIDRange), so their
+// first ID is checked too. Errors have a Bit of -1.
+func NewRangeChecker(min, max int64, gen Interface) (Interface, error) {
+	if min > max {
+		return nil, fmt.Errorf("NewRangeChecker(%d, %d): min must not exceed max", min, max)
+	}
+	return rangeChecker{gen: gen, name: fmt.Sprintf("%T", gen), min: min, max: max}, nil
+}
+
+// NewShifted wraps an ID generator to left-shift its IDs by bits, to place them in a
+// field of a composite ID. Snowflake-like generators OR together a shifted timestamp, a
+// shifted nodeMask and a sequence, each one wrapped by NewOverflowChecker before shifting
+// so that fields never overlap.
+func NewShifted(gen Interface, bits byte) Interface {
+	return shifted{gen: gen, bits: bits}
+}
+
+// NewConstant returns an ID generator that always returns v, such as the nodeMask of
+// Snowflake-like IDs. It's NewIDs method only accepts n=1.
+func NewConstant(v int64) Interface {
+	return constant(v)
+}
+
 // NewTimestamp returns an ID generator that uses the machine clock (Millisecond precision).
 // So it is safe for concurrent use by itself (neither does it check for clashes).
 // It's NewIDs method only accepts n=1.
 func NewTimestamp() Interface {
-	return tstamp{}
+	return NewTimestampClock(SystemClock)
+}
+
+// NewTimestampClock is like NewTimestamp, using c instead of the machine clock. It is
+// as safe for concurrent use as c.
+func NewTimestampClock(c Clock) Interface {
+	return tstamp{unit: int64(time.Millisecond), clock: c}
+}
+
+// NewTimestampMicro is like NewTimestamp, with Microsecond precision.
+func NewTimestampMicro() Interface {
+	return NewTimestampUnit(SystemClock, time.Microsecond)
+}
+
+// NewTimestampUnit is like NewTimestampClock, counting units (e.g. 10 Milliseconds or a
+// Second) instead of Milliseconds. Coarser units need fewer bits for the same lifetime.
+func NewTimestampUnit(c Clock, unit time.Duration) Interface {
+	return tstamp{unit: int64(unit), clock: c}
 }
 
 // Implementation
@@ -80,21 +179,42 @@ type (
 	// It cannot generated more than one ID at once.
 	constant int64
 	// sequential generates IDs by adding to internal counter. It's safe for concurrent use.
+	// value has a cache line of its own, so that counters allocated together (e.g. in an
+	// array or with the fields of the generator using it) do not contend with each other.
+	// IDs past ceiling (if bounded, otherwise math.MaxInt64) fail with ErrExhausted.
 	sequential struct {
-		value int64
+		_       [cacheLine]byte
+		value   int64
+		ceiling int64
+		bounded bool
+		_       [cacheLine - 17]byte
+	}
+	// tstamp counts units (in Nanoseconds) since epoch (Unix Nanoseconds).
+	tstamp struct {
+		epoch, unit int64
+		clock       Clock
 	}
-	tstamp struct{}
 	// overflowChecker executes gen and checks for overflow.
 	overflowChecker struct {
-		gen          Interface
+		gen Interface
+		// name is gen's type for errors, built once so that failures only allocate the
+		// Error itself.
+		name         string
 		overflowBits int64
 	}
+	// rangeChecker executes gen and checks that IDs are between min and max.
+	rangeChecker struct {
+		gen      Interface
+		name     string
+		min, max int64
+	}
 	// shifted executen gen and left-shifts the generated ID's bits.
 	shifted struct {
 		gen  Interface
 		bits byte
 	}
-	// snowflake combines a timestamp, a (constant) nodeMask and a sequence.
+	// snowflake combines a timestamp, a (constant) nodeMask and a sequence, which is
+	// left-shifted by seqBits.
 	snowflake struct {
 		sync.Mutex
 		lastTimestamp int64
@@ -102,21 +222,43 @@ type (
 		constant      Interface
 		seqChecker    Interface
 		sequential    *sequential
+		seqBits       byte
+		// seqSize is the number of IDs per timestamp.
+		seqSize int64
+		// wait for the next timestamp when the sequence overflows.
+		wait bool
+		// waitClock makes it wait when the clock moves backwards.
+		waitClock bool
+		// store persists horizon, advanced by ahead whenever the clock reaches it.
+		store   TimestampStore
+		ahead   int64
+		horizon int64
+		// floor is the timestamp loaded from store: only later ones may be used.
+		floor  int64
+		loaded bool
+		// stats is nil unless WithExpvar is used.
+		stats *snowflakeStats
+		// monitor, if not nil, pauses generation while the clock is skewed.
+		monitor *ClockMonitor
+		// tolerance is the largest shifted timestamp regression absorbed.
+		tolerance uint64
+		// maxWait bounds waits, if positive.
+		maxWait time.Duration
 	}
 )
 
 func (c constant) NewIDs(n int64) (int64, error) {
-	if err := checkNIsOne(c, n); err != nil {
-		return 0, err
+	if n != 1 {
+		return 0, unsupportedCount(c, n)
 	}
 	return int64(c), nil
 }
 
 func (t tstamp) NewIDs(n int64) (int64, error) {
-	if err := checkNIsOne(t, n); err != nil {
-		return 0, err
+	if n != 1 {
+		return 0, unsupportedCount(t, n)
 	}
-	return time.Now().UnixNano() / int64(time.Millisecond), nil
+	return (t.clock.Now() - t.epoch) / t.unit, nil
 }
 
 func (o overflowChecker) NewIDs(n int64) (int64, error) {
@@ -124,8 +266,26 @@ func (o overflowChecker) NewIDs(n int64) (int64, error) {
 	if err != nil {
 		return 0, err
 	}
-	if bits := v & o.overflowBits; bits != 0 {
-		return 0, fmt.Errorf("%T.NewIDs() overflow %b", o.gen, bits)
+	if over := v & o.overflowBits; over != 0 {
+		return 0, &Error{
+			Generator: o.name,
+			Count:     n,
+			Value:     v,
+			Bit:       bits.TrailingZeros64(uint64(over)),
+			Err:       ErrOverflow,
+		}
+	}
+	return v, nil
+}
+
+func (r rangeChecker) NewIDs(n int64) (int64, error) {
+	v, err := r.gen.NewIDs(n)
+	if err != nil {
+		return 0, err
+	}
+	// v-r.min may not fit in int64, but does in uint64.
+	if v < r.min || v > r.max || n > 1 && uint64(v-r.min) < uint64(n-1) {
+		return 0, &Error{Generator: r.name, Count: n, Value: v, Bit: -1, Err: ErrOverflow}
 	}
 	return v, nil
 }
@@ -139,7 +299,36 @@ func (s shifted) NewIDs(n int64) (int64, error) {
 }
 
 func (s *sequential) NewIDs(n int64) (int64, error) {
-	return atomic.AddInt64(&s.value, n), nil
+	ceiling := int64(math.MaxInt64)
+	if s.bounded {
+		ceiling = s.ceiling
+	}
+	v, ok := addUpTo(&s.value, n, ceiling)
+	if !ok {
+		return 0, &Error{
+			Generator: fmt.Sprintf("%T", s),
+			Count:     n,
+			Value:     v,
+			Bit:       -1,
+			Err:       ErrExhausted,
+		}
+	}
+	return v, nil
+}
+
+// addUpTo atomically adds n to *value unless the sum would exceed ceiling, returning it
+// (or the current value, with false).
+func addUpTo(value *int64, n, ceiling int64) (int64, bool) {
+	for {
+		v := atomic.LoadInt64(value)
+		// ceiling-v may not fit in int64, but does in uint64.
+		if n > 0 && (v > ceiling || uint64(ceiling-v) < uint64(n)) {
+			return v, false
+		}
+		if atomic.CompareAndSwapInt64(value, v, v+n) {
+			return v + n, true
+		}
+	}
 }
 
 // reset is used by snowflake to restart the sequence when the timestamp changes.
@@ -150,32 +339,136 @@ func (s *sequential) reset(v int64) {
 func (s *snowflake) NewIDs(n int64) (int64, error) {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
+	return s.newIDs(context.Background(), n, s.wait)
+}
 
+// NewIDsContext is like NewIDs, failing with ErrDeadline instead of waiting past the
+// deadline of ctx, and with its error if it is canceled.
+func (s *snowflake) NewIDsContext(ctx context.Context, n int64) (int64, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	return s.newIDs(ctx, n, s.wait)
+}
+
+// newIDs implements NewIDs, waiting for the next timestamp on overflow if wait is set.
+// s must be locked.
+func (s *snowflake) newIDs(ctx context.Context, n int64, wait bool) (int64, error) {
 	var err error
+	var start time.Time
 	var tstamp, nodeMask, seqNum int64
-	if tstamp, err = s.tstamp.NewIDs(1); err != nil {
-		return 0, err
+	var regressed, overflowed bool
+	if s.monitor != nil && !s.monitor.Healthy() {
+		return 0, ErrClockSkew
+	}
+	if s.store != nil && !s.loaded {
+		if s.floor, err = s.store.Load(); err != nil {
+			return 0, err
+		}
+		s.horizon = s.floor
+		s.loaded = true
+	}
+	for {
+		if tstamp, err = s.tstamp.NewIDs(1); err != nil {
+			return 0, err
+		}
+		// Shifted timestamps are compared unsigned, so layouts may use bit 63 (see
+		// Layout.NewUnsignedSnowflake).
+		if s.lastTimestamp != -1 && uint64(tstamp) < uint64(s.lastTimestamp) &&
+			uint64(s.lastTimestamp)-uint64(tstamp) <= s.tolerance {
+			// Small regression (e.g. leap second smearing): stay on the last timestamp.
+			tstamp = s.lastTimestamp
+		}
+		if s.lastTimestamp != -1 && uint64(tstamp) < uint64(s.lastTimestamp) ||
+			(s.store != nil && uint64(tstamp) <= uint64(s.floor)) {
+			if !regressed {
+				regressed = true
+				s.stats.regression()
+			}
+			if !s.waitClock {
+				return 0, ErrClockMovedBack
+			}
+			// Poll until the clock catches up.
+			if err = s.sleep(ctx, &start); err != nil {
+				return 0, err
+			}
+			continue
+		}
+		if tstamp != s.lastTimestamp {
+			// Requests larger than the whole sequence fail even on a fresh timestamp.
+			s.sequential.reset(-1)
+			if seqNum, err = s.seqChecker.NewIDs(n); err != nil {
+				s.stats.overflow()
+				return 0, err
+			}
+			s.lastTimestamp = tstamp
+			break
+		}
+		if seqNum, err = s.seqChecker.NewIDs(n); err == nil {
+			break
+		}
+		if !overflowed {
+			overflowed = true
+			s.stats.overflow()
+		}
+		if !wait {
+			return 0, err
+		}
+		// Sequence exhausted: poll until the next timestamp.
+		if err = s.sleep(ctx, &start); err != nil {
+			return 0, err
+		}
 	}
 
-	if tstamp != s.lastTimestamp {
-		seqNum = n - 1
-		s.sequential.reset(seqNum)
-		s.lastTimestamp = tstamp
-	} else if seqNum, err = s.seqChecker.NewIDs(n); err != nil {
-		return 0, err
+	if s.store != nil && uint64(tstamp) >= uint64(s.horizon) {
+		// Reserve ahead of the clock, so a crash never exposes used timestamps.
+		if err = s.store.Save(tstamp + s.ahead); err != nil {
+			return 0, err
+		}
+		s.horizon = tstamp + s.ahead
 	}
 
 	if nodeMask, err = s.constant.NewIDs(1); err != nil {
 		return 0, err
 	}
 
-	return tstamp | nodeMask | seqNum, nil
+	s.stats.issue(n, tstamp)
+	return tstamp | nodeMask | seqNum<<s.seqBits, nil
 }
 
-func checkNIsOne(gen Interface, n int64) error {
-	if n != 1 {
-		return fmt.Errorf("%T/%v.NewIDs() supports count=1, got %v",
-			gen, gen, n)
+// cacheLine is the size of a CPU cache line on common architectures, for padding.
+const cacheLine = 64
+
+// waitInterval is the polling interval when waiting for the clock.
+const waitInterval = 100 * time.Microsecond
+
+// sleep waits for waitInterval, unless that would exceed the wait allowed since *start
+// (set on the first call) or the deadline of ctx.
+func (s *snowflake) sleep(ctx context.Context, start *time.Time) error {
+	now := time.Now()
+	if start.IsZero() {
+		*start = now
+	}
+	if err := ctx.Err(); err != nil {
+		return contextError(err)
+	}
+	if s.maxWait > 0 && now.Add(waitInterval).Sub(*start) > s.maxWait {
+		return ErrWouldBlock
 	}
+	if d, ok := ctx.Deadline(); ok && now.Add(waitInterval).After(d) {
+		return ErrDeadline
+	}
+	time.Sleep(waitInterval)
 	return nil
 }
+
+// unsupportedCount returns the error for generators that do not accept n. Call it only
+// on failure: converting gen to Interface may allocate.
+func unsupportedCount(gen Interface, n int64) error {
+	return &Error{
+		Generator: fmt.Sprintf("%T", gen),
+		Count:     n,
+		Value:     n,
+		Bit:       -1,
+		Err:       ErrUnsupportedCount,
+	}
+}
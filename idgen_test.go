@@ -2,8 +2,8 @@ package idgen
 
 import (
 	"errors"
-	"fmt"
 	"math"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -45,6 +45,67 @@ func TestSequential(t *testing.T) {
 	}
 }
 
+func TestSequentialFrom(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		start, count, expected int64
+	}{
+		{1000, 1, 1000},
+		{1000, 5, 1004},
+		{0, 1, 0},
+		{-5, 3, -3},
+		{math.MinInt64, 1, math.MinInt64},
+		{math.MaxInt64, 1, math.MaxInt64},
+	}
+	for i, test := range tests {
+		if v, err := NewSequentialFrom(test.start).NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("TestSequentialFrom %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+}
+
+func TestSequentialExhausted(t *testing.T) {
+	t.Parallel()
+	if _, err := NewBoundedSequential(10, 9); err == nil {
+		t.Error("TestSequentialExhausted: expected error for start > ceiling")
+	}
+	bounded, _ := NewBoundedSequential(-10, 10)
+	lowest, _ := NewBoundedSequential(math.MinInt64, math.MinInt64+1)
+	var tests = []struct {
+		gen             Interface
+		count, expected int64
+		err             error
+	}{
+		{NewSequentialFrom(math.MaxInt64 - 2), 3, math.MaxInt64, nil},
+		{NewSequentialFrom(math.MaxInt64 - 2), 4, 0, ErrExhausted},
+		{NewSequentialFrom(math.MaxInt64), 1, math.MaxInt64, nil},
+		{NewNegSequential(), math.MaxInt64, -1, nil},
+		{bounded, 15, 4, nil},
+		{bounded, 7, 0, ErrExhausted},
+		{bounded, 6, 10, nil}, // Failures do not consume IDs.
+		{bounded, 1, 0, ErrExhausted},
+		{bounded, 0, 10, nil},
+		{lowest, 2, math.MinInt64 + 1, nil},
+		{lowest, 1, 0, ErrExhausted},
+		{NewSequentialFrom(math.MinInt64), math.MaxInt64, -2, nil},
+	}
+	for i, test := range tests {
+		v, err := test.gen.NewIDs(test.count)
+		if v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("TestSequentialExhausted %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	gen := NewSequentialFrom(math.MaxInt64)
+	gen.NewIDs(1)
+	for i := 0; i < 2; i++ {
+		var e *Error
+		if _, err := gen.NewIDs(1); !errors.As(err, &e) || e.Value != math.MaxInt64 || e.Bit != -1 {
+			t.Errorf("TestSequentialExhausted: got error %v, expected %v", err, ErrExhausted)
+		}
+	}
+}
+
 func TestNegSequential(t *testing.T) {
 	t.Parallel()
 	gen := NewNegSequential()
@@ -86,6 +147,18 @@ func TestTimestamp(t *testing.T) {
 	}
 }
 
+func TestTimestampClock(t *testing.T) {
+	t.Parallel()
+	now := int64(1500 * time.Millisecond)
+	gen := NewTimestampClock(ClockFunc(func() int64 { return now }))
+	for _, expected := range []int64{1500, 1501} {
+		if v, err := gen.NewIDs(1); err != nil || v != expected {
+			t.Errorf("TestTimestampClock: got %v (error %v), expected %v", v, err, expected)
+		}
+		now += int64(time.Millisecond)
+	}
+}
+
 func TestOverflowChecker(t *testing.T) {
 	t.Parallel()
 	var tests = []struct {
@@ -116,6 +189,36 @@ func TestOverflowChecker(t *testing.T) {
 	}
 }
 
+func TestRangeChecker(t *testing.T) {
+	t.Parallel()
+	if _, err := NewRangeChecker(1, 0, repeat{}); err == nil {
+		t.Error("TestRangeChecker: expected error for min > max")
+	}
+	var tests = []struct {
+		start, count, min, max, expected int64
+		err                              error
+	}{
+		{-128, 1, -128, 127, -128, nil},
+		{-129, 1, -128, 127, 0, ErrOverflow},
+		{-129, 2, -128, 127, 0, ErrOverflow}, // -129 and -128.
+		{125, 3, -128, 127, 127, nil},
+		{125, 4, -128, 127, 0, ErrOverflow},
+		{math.MinInt64 + 1, 1, math.MinInt64 + 1, -1, math.MinInt64 + 1, nil},
+		{-3, 3, math.MinInt64, -1, -1, nil},
+		{-3, 4, math.MinInt64, -1, 0, ErrOverflow},
+		{math.MinInt64 + 1, math.MaxInt64, math.MinInt64, math.MaxInt64, -1, nil},
+	}
+	for i, test := range tests {
+		gen, _ := NewRangeChecker(test.min, test.max, NewSequentialFrom(test.start))
+		v, err := gen.NewIDs(test.count)
+		var e *Error
+		if v != test.expected || !errors.Is(err, test.err) || err != nil && (!errors.As(err, &e) || e.Bit != -1) {
+			t.Errorf("TestRangeChecker %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+}
+
 func TestShifted(t *testing.T) {
 	t.Parallel()
 	var tests = []struct {
@@ -171,8 +274,7 @@ func TestErrorPropagation(t *testing.T) {
 func TestSnowFlakeErrorPropagation(t *testing.T) {
 	t.Parallel()
 	gen := NewSnowflake(1 << 12)
-	expected := fmt.Errorf("%T.NewIDs() overflow 1000000000000",
-		gen.(*snowflake).constant.(shifted).gen.(overflowChecker).gen)
+	expected := &Error{"idgen.constant", 1, 1 << 12, 12, ErrOverflow}
 	if _, err := gen.NewIDs(1); !matchErrors(err, expected) {
 		t.Errorf("TestSnowFlakeErrorPropagation: got error %q, expected error %q",
 			err, expected)
@@ -184,8 +286,7 @@ func TestSnowFlakeErrorPropagation(t *testing.T) {
 	}{
 		{nil, 1 << 12},
 		{
-			fmt.Errorf("%T.NewIDs() overflow 1000000000000",
-				gen.(*snowflake).seqChecker.(overflowChecker).gen),
+			&Error{"*idgen.sequential", 1, 1 << 12, 12, ErrOverflow},
 			1,
 		},
 	}
@@ -197,6 +298,51 @@ func TestSnowFlakeErrorPropagation(t *testing.T) {
 	}
 }
 
+func TestSentinelErrors(t *testing.T) {
+	t.Parallel()
+	now := int64(time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	var tests = []struct {
+		gen      Interface
+		count    int64
+		expected error
+	}{
+		{NewOverflowChecker(2, NewSequential()), 4, ErrOverflow},
+		{NewSnowflake(1 << 10), 1, ErrOverflow},
+		{constant(1), 2, ErrUnsupportedCount},
+		{NewTimestamp(), 0, ErrUnsupportedCount},
+		{NewSnowflake(0, WithClock(clock)), 1, nil},
+	}
+	for i, test := range tests {
+		if _, err := test.gen.NewIDs(test.count); !errors.Is(err, test.expected) {
+			t.Errorf("TestSentinelErrors %d: got error %v, expected %v", i, err, test.expected)
+		}
+	}
+	gen := tests[len(tests)-1].gen
+	now = 0
+	if _, err := gen.NewIDs(1); !errors.Is(err, ErrClockMovedBack) {
+		t.Errorf("TestSentinelErrors: got error %v, expected %v", err, ErrClockMovedBack)
+	}
+}
+
+func TestError(t *testing.T) {
+	t.Parallel()
+	_, err := NewOverflowChecker(4, NewSequential()).NewIDs(20)
+	var e *Error
+	switch {
+	case !errors.As(err, &e):
+		t.Errorf("TestError: got error %v, expected *Error", err)
+	case *e != Error{"*idgen.sequential", 20, 20, 4, ErrOverflow}:
+		t.Errorf("TestError: got %+v", *e)
+	case err.Error() != "*idgen.sequential.NewIDs(20): 20 overflows at bit 4: idgen: overflow":
+		t.Errorf("TestError: got message %q", err)
+	}
+	_, err = NewTimestamp().NewIDs(3)
+	if err.Error() != "idgen.tstamp.NewIDs(3): idgen: unsupported count" {
+		t.Errorf("TestError: got message %q", err)
+	}
+}
+
 func matchErrors(a, b error) bool {
 	var s1, s2 string
 	if a != nil {
@@ -235,6 +381,145 @@ func TestSnowflake(t *testing.T) {
 	}
 }
 
+func TestDecomposeSnowflake(t *testing.T) {
+	gen := NewSnowflake(1023)
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NewIDs(3)
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("TestDecomposeSnowflake: got error %q", err)
+	}
+	ts, node, seq := DecomposeSnowflake(id)
+	switch {
+	case ts.Before(before) || ts.After(after):
+		t.Errorf("TestDecomposeSnowflake: got time %v, expected between %v and %v",
+			ts, before, after)
+	case node != 1023:
+		t.Errorf("TestDecomposeSnowflake: got node %v, expected 1023", node)
+	case seq != 2:
+		t.Errorf("TestDecomposeSnowflake: got sequence %v, expected 2", seq)
+	}
+}
+
+func TestSnowflakeClockRollback(t *testing.T) {
+	future := func() int64 {
+		return (time.Now().UnixNano()/int64(time.Millisecond) + 5) << 22
+	}
+	gen := NewSnowflake(1)
+	gen.(*snowflake).lastTimestamp = future()
+	if v, err := gen.NewIDs(1); err != ErrClockMovedBack {
+		t.Errorf("TestSnowflakeClockRollback: got %v (error %v), expected error %q",
+			v, err, ErrClockMovedBack)
+	}
+
+	gen = NewSnowflake(1, WithWaitOnClockRollback())
+	last := future()
+	gen.(*snowflake).lastTimestamp = last
+	v, err := gen.NewIDs(1)
+	switch {
+	case err != nil:
+		t.Errorf("TestSnowflakeClockRollback: got error %q", err)
+	case v < last:
+		t.Errorf("TestSnowflakeClockRollback: got %v, expected at least %v", v, last)
+	}
+}
+
+func TestSnowflakeWithClock(t *testing.T) {
+	t.Parallel()
+	now := int64(time.Millisecond)
+	gen := NewSnowflake(3, WithClock(ClockFunc(func() int64 { return now })))
+	var tests = []struct {
+		count, advance, expected int64
+	}{
+		{1, 0, 1<<22 | 3<<12},
+		{2, 0, 1<<22 | 3<<12 | 2},
+		{1, int64(time.Millisecond), 2<<22 | 3<<12},
+		{4096, 0, 0},
+		{1, int64(time.Millisecond), 3<<22 | 3<<12},
+	}
+	for i, test := range tests {
+		now += test.advance
+		v, err := gen.NewIDs(test.count)
+		switch {
+		case test.expected == 0 && err == nil:
+			t.Errorf("TestSnowflakeWithClock %d: expected error, got %v", i, v)
+		case test.expected != 0 && (err != nil || v != test.expected):
+			t.Errorf("TestSnowflakeWithClock %d: got %v (error %v), expected %v",
+				i, v, err, test.expected)
+		}
+	}
+}
+
+func TestSnowflakeClockTolerance(t *testing.T) {
+	t.Parallel()
+	// Smear profile: the clock wobbles back by fractions of a Millisecond, then is set
+	// back by 6 Milliseconds.
+	var tests = []struct {
+		now      time.Duration
+		expected int64
+		err      error
+	}{
+		{10900 * time.Microsecond, 10<<22 | 1<<12, nil},
+		{10200 * time.Microsecond, 10<<22 | 1<<12 | 1, nil},
+		{9950 * time.Microsecond, 10<<22 | 1<<12 | 2, nil},
+		{10100 * time.Microsecond, 10<<22 | 1<<12 | 3, nil},
+		{11 * time.Millisecond, 11<<22 | 1<<12, nil},
+		{5 * time.Millisecond, 0, ErrClockMovedBack},
+		{11500 * time.Microsecond, 11<<22 | 1<<12 | 1, nil},
+	}
+	var now time.Duration
+	clock := ClockFunc(func() int64 { return int64(now) })
+	gen := NewSnowflake(1, WithClock(clock), WithClockTolerance(500*time.Microsecond))
+	for i, test := range tests {
+		now = test.now
+		if v, err := gen.NewIDs(1); v != test.expected || err != test.err {
+			t.Errorf("TestSnowflakeClockTolerance %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+
+	// Without tolerance, the same wobble is an error.
+	gen = NewSnowflake(1, WithClock(clock))
+	now = 10900 * time.Microsecond
+	gen.NewIDs(1)
+	now = 9950 * time.Microsecond
+	if v, err := gen.NewIDs(1); err != ErrClockMovedBack {
+		t.Errorf("TestSnowflakeClockTolerance: got %v (error %v), expected error %v",
+			v, err, ErrClockMovedBack)
+	}
+}
+
+func TestSonyflake(t *testing.T) {
+	gen := NewSonyflake(0xabcd)
+	var machine int64 = 0xabcd
+	var counter int64
+	unit := int64(10 * time.Millisecond)
+	for i := int64(0); i < 10; i++ {
+		if i%2 == 0 {
+			// Same as TestSnowflake, with 10 Millisecond resolution.
+			time.Sleep(time.Duration(unit))
+			counter = 0
+		}
+		counter += i + 1
+		v, err := gen.NewIDs(i + 1)
+		tstamp := (time.Now().UnixNano() - SonyflakeEpoch.UnixNano()) / unit
+		expected := tstamp<<24 | (counter-1)<<16 | machine
+		switch {
+		case err != nil:
+			t.Errorf("TestSonyflake %d: expected %v, got error %q", i, expected, err)
+		case v>>24 != tstamp && v>>24 != tstamp-1:
+			t.Errorf("TestSonyflake %d: expected timestamp %v, got %v", i, tstamp, v>>24)
+		case v&(1<<24-1) != expected&(1<<24-1):
+			t.Errorf("TestSonyflake %d: expected %x, got %x", i, expected, v)
+		default:
+			// success
+		}
+	}
+	if _, err := gen.NewIDs(256); err == nil {
+		t.Errorf("TestSonyflake: expected sequence overflow")
+	}
+}
+
 type repeat struct{}
 
 func (r repeat) NewIDs(count int64) (int64, error) {
@@ -245,6 +530,114 @@ type broken struct {
 	error
 }
 
+var errTest = errors.New("broken ID generator is broken")
+
 func (b broken) NewIDs(count int64) (int64, error) {
 	return 0, b.error
 }
+
+func TestAllocs(t *testing.T) {
+	var tests = []struct {
+		name     string
+		gen      Interface
+		count    int64
+		expected float64
+	}{
+		{"constant", constant(1000), 1, 0},
+		{"timestamp", NewTimestamp(), 1, 0},
+		{"overflowChecker", NewOverflowChecker(62, NewSequential()), 1, 0},
+		{"snowflake", NewSnowflake(1, WithWaitOnOverflow()), 1, 0},
+		{"overflowChecker failure", NewOverflowChecker(0, NewSequential()), 1, 1},
+	}
+	for _, test := range tests {
+		allocs := testing.AllocsPerRun(100, func() { test.gen.NewIDs(test.count) })
+		if allocs != test.expected {
+			t.Errorf("TestAllocs %s: got %v allocations, expected %v", test.name, allocs, test.expected)
+		}
+	}
+}
+
+func BenchmarkOverflowChecker(b *testing.B) {
+	gen := NewOverflowChecker(62, NewSequential())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		gen.NewIDs(1)
+	}
+}
+
+func BenchmarkOverflowCheckerFailure(b *testing.B) {
+	gen := NewOverflowChecker(0, NewSequential())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		gen.NewIDs(1)
+	}
+}
+
+// unpadded is sequential without padding, for comparison.
+type unpadded struct {
+	value int64
+}
+
+func (u *unpadded) NewIDs(n int64) (int64, error) {
+	v, _ := addUpTo(&u.value, n, math.MaxInt64)
+	return v, nil
+}
+
+// benchmarkAdjacent spreads concurrent calls over two generators allocated together.
+func benchmarkAdjacent(b *testing.B, gens [2]Interface) {
+	var next int32
+	b.RunParallel(func(pb *testing.PB) {
+		gen := gens[atomic.AddInt32(&next, 1)%2]
+		for pb.Next() {
+			gen.NewIDs(1)
+		}
+	})
+}
+
+func BenchmarkSequentialAdjacent(b *testing.B) {
+	b.Run("padded", func(b *testing.B) {
+		var seqs [2]sequential
+		benchmarkAdjacent(b, [2]Interface{&seqs[0], &seqs[1]})
+	})
+	b.Run("unpadded", func(b *testing.B) {
+		var seqs [2]unpadded
+		benchmarkAdjacent(b, [2]Interface{&seqs[0], &seqs[1]})
+	})
+}
+
+func BenchmarkConstant(b *testing.B) {
+	gen := Interface(constant(1000))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		gen.NewIDs(1)
+	}
+}
+
+func TestComposeBuildingBlocks(t *testing.T) {
+	t.Parallel()
+	// A 3-bit node field above an 8-bit sequence, assembled by hand.
+	seq := NewOverflowChecker(8, NewSequential())
+	node := NewShifted(NewOverflowChecker(3, NewConstant(5)), 8)
+	var tests = []struct {
+		count, expected int64
+		err             error
+	}{
+		{1, 5<<8 | 1, nil},
+		{254, 5<<8 | 255, nil},
+		{1, 0, ErrOverflow},
+	}
+	for i, test := range tests {
+		s, err := seq.NewIDs(test.count)
+		var n int64
+		if err == nil {
+			n, err = node.NewIDs(1)
+		}
+		if !errors.Is(err, test.err) || (err == nil && n|s != test.expected) {
+			t.Errorf("TestComposeBuildingBlocks %d: got %v (error %v), expected %v",
+				i, n|s, err, test.expected)
+		}
+	}
+	if _, err := NewConstant(1).NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestComposeBuildingBlocks: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+}
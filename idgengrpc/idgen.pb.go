@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: idgen.proto
+
+package idgengrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// count is the number of IDs to generate, at least 1.
+	Count         int64 `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_idgen_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateRequest) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GenerateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ids in generation order.
+	Ids           []int64 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_idgen_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_idgen_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_idgen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+var File_idgen_proto protoreflect.FileDescriptor
+
+const file_idgen_proto_rawDesc = "" +
+	"\n" +
+	"\vidgen.proto\x12\bidgen.v1\"'\n" +
+	"\x0fGenerateRequest\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\"$\n" +
+	"\x10GenerateResponse\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids2\x99\x01\n" +
+	"\tIDService\x12A\n" +
+	"\bGenerate\x12\x19.idgen.v1.GenerateRequest\x1a\x1a.idgen.v1.GenerateResponse\x12I\n" +
+	"\x0eGenerateStream\x12\x19.idgen.v1.GenerateRequest\x1a\x1a.idgen.v1.GenerateResponse0\x01B'Z%github.com/carloslenz/idgen/idgengrpcb\x06proto3"
+
+var (
+	file_idgen_proto_rawDescOnce sync.Once
+	file_idgen_proto_rawDescData []byte
+)
+
+func file_idgen_proto_rawDescGZIP() []byte {
+	file_idgen_proto_rawDescOnce.Do(func() {
+		file_idgen_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_idgen_proto_rawDesc), len(file_idgen_proto_rawDesc)))
+	})
+	return file_idgen_proto_rawDescData
+}
+
+var file_idgen_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_idgen_proto_goTypes = []any{
+	(*GenerateRequest)(nil),  // 0: idgen.v1.GenerateRequest
+	(*GenerateResponse)(nil), // 1: idgen.v1.GenerateResponse
+}
+var file_idgen_proto_depIdxs = []int32{
+	0, // 0: idgen.v1.IDService.Generate:input_type -> idgen.v1.GenerateRequest
+	0, // 1: idgen.v1.IDService.GenerateStream:input_type -> idgen.v1.GenerateRequest
+	1, // 2: idgen.v1.IDService.Generate:output_type -> idgen.v1.GenerateResponse
+	1, // 3: idgen.v1.IDService.GenerateStream:output_type -> idgen.v1.GenerateResponse
+	2, // [2:4] is the sub-list for method output_type
+	0, // [0:2] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_idgen_proto_init() }
+func file_idgen_proto_init() {
+	if File_idgen_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_idgen_proto_rawDesc), len(file_idgen_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_idgen_proto_goTypes,
+		DependencyIndexes: file_idgen_proto_depIdxs,
+		MessageInfos:      file_idgen_proto_msgTypes,
+	}.Build()
+	File_idgen_proto = out.File
+	file_idgen_proto_goTypes = nil
+	file_idgen_proto_depIdxs = nil
+}
@@ -0,0 +1,74 @@
+// Package idgengrpc serves ID generators over gRPC, with the IDService defined in
+// idgen.proto.
+package idgengrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative idgen.proto
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/carloslenz/idgen"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements IDServiceServer with an idgen.Interface. Register it with
+// RegisterIDServiceServer. Safe for concurrent use if the generator is.
+type Server struct {
+	UnimplementedIDServiceServer
+	gen      idgen.Interface
+	maxBatch int64
+}
+
+// NewServer returns a Server generating with gen, requesting at most maxBatch IDs per
+// NewIDs call (e.g. the sequence capacity of a Snowflake), which is also the limit of
+// unary Generate calls.
+func NewServer(gen idgen.Interface, maxBatch int64) (*Server, error) {
+	if maxBatch < 1 {
+		return nil, fmt.Errorf("NewServer(%d): batch limit must be positive", maxBatch)
+	}
+	return &Server{gen: gen, maxBatch: maxBatch}, nil
+}
+
+// Generate implements IDServiceServer.
+func (s *Server) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+	if req.GetCount() < 1 || req.GetCount() > s.maxBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "count must be between 1 and %d, got %d",
+			s.maxBatch, req.GetCount())
+	}
+	return s.generate(req.GetCount())
+}
+
+// GenerateStream implements IDServiceServer.
+func (s *Server) GenerateStream(req *GenerateRequest, stream IDService_GenerateStreamServer) error {
+	if req.GetCount() < 1 {
+		return status.Errorf(codes.InvalidArgument, "count must be positive, got %d", req.GetCount())
+	}
+	for left := req.GetCount(); left > 0; {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		resp, err := s.generate(min(left, s.maxBatch))
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		left -= int64(len(resp.Ids))
+	}
+	return nil
+}
+
+func (s *Server) generate(n int64) (*GenerateResponse, error) {
+	r, err := idgen.NewIDRange(s.gen, n)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	ids := make([]int64, 0, r.Len())
+	for id := range r.All() {
+		ids = append(ids, id)
+	}
+	return &GenerateResponse{Ids: ids}, nil
+}
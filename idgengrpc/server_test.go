@@ -0,0 +1,86 @@
+package idgengrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dial(t *testing.T, gen idgen.Interface, maxBatch int64) IDServiceClient {
+	srv, err := NewServer(gen, maxBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	RegisterIDServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return NewIDServiceClient(conn)
+}
+
+func TestGenerate(t *testing.T) {
+	client := dial(t, idgen.NewOverflowChecker(3, idgen.NewSequential()), 4)
+	ctx := context.Background()
+	var tests = []struct {
+		count    int64
+		expected []int64
+		code     codes.Code
+	}{
+		{3, []int64{1, 2, 3}, codes.OK},
+		{5, nil, codes.InvalidArgument},
+		{0, nil, codes.InvalidArgument},
+		{4, []int64{4, 5, 6, 7}, codes.OK},
+		{4, nil, codes.Unavailable},
+	}
+	for i, test := range tests {
+		resp, err := client.Generate(ctx, &GenerateRequest{Count: test.count})
+		if status.Code(err) != test.code || !slices.Equal(resp.GetIds(), test.expected) {
+			t.Errorf("TestGenerate %d: got %v (error %v), expected %v (%v)",
+				i, resp.GetIds(), err, test.expected, test.code)
+		}
+	}
+}
+
+func TestGenerateStream(t *testing.T) {
+	client := dial(t, idgen.NewSequential(), 4)
+	stream, err := client.GenerateStream(context.Background(), &GenerateRequest{Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var batches [][]int64
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		batches = append(batches, resp.GetIds())
+	}
+	expected := [][]int64{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10}}
+	if !slices.EqualFunc(batches, expected, slices.Equal) {
+		t.Errorf("TestGenerateStream: got %v, expected %v", batches, expected)
+	}
+	if _, err := NewServer(idgen.NewSequential(), 0); err == nil {
+		t.Errorf("TestGenerateStream: expected error for zero batch limit")
+	}
+}
@@ -0,0 +1,108 @@
+package idgenhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+// maxBodyBytes limits batch request bodies, which only carry a count.
+const maxBodyBytes = 1 << 10
+
+// BatchConfig configures BatchHandler.
+type BatchConfig struct {
+	// MaxCount is the largest accepted count, 1000 if zero.
+	MaxCount int64
+	// Layout, if not nil, adds the node and timestamp of the first ID to responses,
+	// decomposed with Options.
+	Layout  *idgen.Layout
+	Options []idgen.Option
+}
+
+type batchRequest struct {
+	Count int64 `json:"count"`
+}
+
+// batchResponse describes the allocated range. IDs are also given as strings, since
+// JavaScript numbers lose precision above 2^53.
+type batchResponse struct {
+	First     int64      `json:"first"`
+	Last      int64      `json:"last"`
+	FirstStr  string     `json:"first_str"`
+	LastStr   string     `json:"last_str"`
+	Step      int64      `json:"step"`
+	Count     int64      `json:"count"`
+	Node      *int64     `json:"node,omitempty"`
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// BatchHandler returns an http.Handler allocating IDs with gen for POST requests with a
+// JSON body like {"count": 100}. It responds with the range, e.g. {"first": 1, "last":
+// 100, "first_str": "1", "last_str": "100", "step": 1, "count": 100}, plus node and
+// timestamp if cfg.Layout is set. Errors are JSON objects like {"error": "..."}.
+func BatchHandler(gen idgen.Interface, cfg BatchConfig) http.Handler {
+	if cfg.MaxCount == 0 {
+		cfg.MaxCount = 1000
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeJSON(w, http.StatusMethodNotAllowed, errorResponse{"method not allowed"})
+			return
+		}
+		var req batchRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			status := http.StatusBadRequest
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			writeJSON(w, status, errorResponse{"invalid request: " + err.Error()})
+			return
+		}
+		if req.Count < 1 || req.Count > cfg.MaxCount {
+			writeJSON(w, http.StatusBadRequest, errorResponse{
+				fmt.Sprintf("count must be between 1 and %d, got %d", cfg.MaxCount, req.Count)})
+			return
+		}
+		rng, err := idgen.NewIDRange(gen, req.Count)
+		if err != nil {
+			writeJSON(w, http.StatusServiceUnavailable, errorResponse{err.Error()})
+			return
+		}
+		resp := batchResponse{
+			First:    rng.First(),
+			Last:     rng.Last(),
+			FirstStr: strconv.FormatInt(rng.First(), 10),
+			LastStr:  strconv.FormatInt(rng.Last(), 10),
+			Step:     rng.Step(),
+			Count:    rng.Len(),
+		}
+		if cfg.Layout != nil {
+			ts, node, _ := cfg.Layout.Decompose(rng.First(), cfg.Options...)
+			ts = ts.UTC()
+			resp.Node, resp.Timestamp = &node, &ts
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
+
+// NewServeMux returns a mux serving Handler on GET /id and BatchHandler on POST /ids.
+func NewServeMux(gen idgen.Interface, cfg BatchConfig) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/id", Handler(gen))
+	mux.Handle("/ids", BatchHandler(gen, cfg))
+	return mux
+}
@@ -0,0 +1,53 @@
+package idgenhttp
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestBatchHandler(t *testing.T) {
+	mux := NewServeMux(idgen.NewSequential(), BatchConfig{MaxCount: 10})
+	var tests = []struct {
+		method, target, body string
+		status               int
+		expected             string
+	}{
+		{"POST", "/ids", `{"count": 3}`, 200,
+			`{"first":1,"last":3,"first_str":"1","last_str":"3","step":1,"count":3}`},
+		{"GET", "/id", "", 200, "4"},
+		{"POST", "/ids", `{"count": 11}`, 400, `{"error":"count must be between 1 and 10, got 11"}`},
+		{"POST", "/ids", `{"count": 0}`, 400, `{"error":"count must be between 1 and 10, got 0"}`},
+		{"POST", "/ids", `{"n": 1}`, 400, `{"error":"invalid request: json: unknown field \"n\""}`},
+		{"POST", "/ids", `{"count": 1` + strings.Repeat(" ", 2000) + `}`, 413, ""},
+		{"GET", "/ids", "", 405, `{"error":"method not allowed"}`},
+	}
+	for i, test := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(test.method, test.target, strings.NewReader(test.body)))
+		body := strings.TrimSpace(rec.Body.String())
+		if rec.Code != test.status || (test.expected != "" && body != test.expected) {
+			t.Errorf("TestBatchHandler %d: got %d %s, expected %d %s",
+				i, rec.Code, body, test.status, test.expected)
+		}
+	}
+}
+
+func TestBatchHandlerLayout(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	clock := idgen.WithClock(idgen.ClockFunc(func() int64 { return now.UnixNano() }))
+	gen := idgen.NewSnowflake(5, clock)
+	h := BatchHandler(gen, BatchConfig{Layout: &idgen.SnowflakeLayout})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("POST", "/ids", strings.NewReader(`{"count": 2}`)))
+	first := now.UnixMilli()<<22 | 5<<12
+	expected := `"step":1,"count":2,"node":5,"timestamp":"2024-05-06T07:08:09Z"}`
+	if rec.Code != 200 || !strings.HasSuffix(strings.TrimSpace(rec.Body.String()), expected) ||
+		!strings.Contains(rec.Body.String(), `"first":`+strconv.FormatInt(first, 10)) {
+		t.Errorf("TestBatchHandlerLayout: got %d %s", rec.Code, rec.Body)
+	}
+}
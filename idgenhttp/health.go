@@ -0,0 +1,151 @@
+package idgenhttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+// HealthConfig configures HealthHandler. Checks with zero settings are skipped.
+type HealthConfig struct {
+	// Layout and Options give the end of the timestamp range (see Layout.Decompose).
+	Layout  *idgen.Layout
+	Options []idgen.Option
+	// MinHeadroom is the minimum time left before timestamps overflow.
+	MinHeadroom time.Duration
+
+	// Clock is the generator's clock, measured for the headroom and against Reference,
+	// idgen.SystemClock if nil. It may be a monotonic clock diverging from the wall clock.
+	Clock idgen.Clock
+
+	// Reference returns the time of a trusted source, to measure the local clock's skew.
+	Reference func(ctx context.Context) (time.Time, error)
+	// MaxSkew is the maximum tolerated difference with Reference, 1 Second if zero.
+	MaxSkew time.Duration
+
+	// Overflows returns the cumulative count of sequence overflows (e.g. from the
+	// "overflows" stat of idgen.WithExpvar). The rate is measured between requests.
+	Overflows func() int64
+	// MaxOverflowRate is the maximum tolerated overflows per second.
+	MaxOverflowRate float64
+
+	// Checks probe backends (e.g. the database of a segment allocator or a node
+	// allocator's lease store) by name.
+	Checks map[string]func(ctx context.Context) error
+}
+
+type healthResponse struct {
+	Status       string            `json:"status"`
+	Problems     []string          `json:"problems,omitempty"`
+	Skew         string            `json:"skew,omitempty"`
+	Headroom     string            `json:"headroom,omitempty"`
+	OverflowRate *float64          `json:"overflow_rate,omitempty"`
+	Checks       map[string]string `json:"checks,omitempty"`
+}
+
+// HealthHandler returns an http.Handler reporting generator health as JSON, with 200 OK
+// if healthy and 503 Service Unavailable otherwise (e.g. for load balancer readiness
+// checks).
+func HealthHandler(cfg HealthConfig) http.Handler {
+	if cfg.MaxSkew == 0 {
+		cfg.MaxSkew = time.Second
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = idgen.SystemClock
+	}
+	var mu sync.Mutex
+	var lastOverflows int64
+	var lastSample time.Time
+	if cfg.Overflows != nil {
+		lastOverflows, lastSample = cfg.Overflows(), time.Now()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp healthResponse
+		now := time.Now()
+		if cfg.Layout != nil {
+			l := *cfg.Layout
+			end, _, _ := l.Decompose((1<<l.TimeBits-1)<<(l.NodeBits+l.SeqBits), cfg.Options...)
+			headroom := end.Sub(time.Unix(0, cfg.Clock.Now()))
+			resp.Headroom = headroom.String()
+			if headroom < cfg.MinHeadroom {
+				resp.Problems = append(resp.Problems,
+					fmt.Sprintf("timestamps overflow in %v, at %v", headroom, end.UTC()))
+			}
+		}
+		if cfg.Reference != nil {
+			local, start := cfg.Clock.Now(), time.Now()
+			ref, err := cfg.Reference(r.Context())
+			if err != nil {
+				resp.Problems = append(resp.Problems, "reference clock: "+err.Error())
+			} else {
+				// Compare with the middle of the round trip.
+				skew := time.Unix(0, local).Add(time.Since(start) / 2).Sub(ref)
+				resp.Skew = skew.String()
+				if skew > cfg.MaxSkew || skew < -cfg.MaxSkew {
+					resp.Problems = append(resp.Problems,
+						fmt.Sprintf("clock skew %v exceeds %v", skew, cfg.MaxSkew))
+				}
+			}
+		}
+		if cfg.Overflows != nil {
+			mu.Lock()
+			count := cfg.Overflows()
+			var rate float64
+			if elapsed := now.Sub(lastSample).Seconds(); elapsed > 0 {
+				rate = float64(count-lastOverflows) / elapsed
+			}
+			lastOverflows, lastSample = count, now
+			mu.Unlock()
+			resp.OverflowRate = &rate
+			if cfg.MaxOverflowRate > 0 && rate > cfg.MaxOverflowRate {
+				resp.Problems = append(resp.Problems,
+					fmt.Sprintf("%.1f overflows per second exceeds %v", rate, cfg.MaxOverflowRate))
+			}
+		}
+		names := make([]string, 0, len(cfg.Checks))
+		for name := range cfg.Checks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if resp.Checks == nil {
+				resp.Checks = map[string]string{}
+			}
+			if err := cfg.Checks[name](r.Context()); err != nil {
+				resp.Checks[name] = err.Error()
+				resp.Problems = append(resp.Problems, name+": "+err.Error())
+			} else {
+				resp.Checks[name] = "ok"
+			}
+		}
+		status := http.StatusOK
+		resp.Status = "ok"
+		if len(resp.Problems) > 0 {
+			status, resp.Status = http.StatusServiceUnavailable, "unhealthy"
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, status, resp)
+	})
+}
+
+// HTTPDateReference returns a HealthConfig.Reference reading the Date header of a HEAD
+// request to url, accurate to about a second.
+func HTTPDateReference(client *http.Client, url string) func(ctx context.Context) (time.Time, error) {
+	return func(ctx context.Context) (time.Time, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return time.Time{}, err
+		}
+		resp.Body.Close()
+		return http.ParseTime(resp.Header.Get("Date"))
+	}
+}
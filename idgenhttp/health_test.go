@@ -0,0 +1,71 @@
+package idgenhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestHealthHandler(t *testing.T) {
+	var overflows int64
+	jsSafe := idgen.JSSafeLayout
+	slow := idgen.Layout{TimeBits: 32, NodeBits: 10, SeqBits: 21, Unit: 2 * time.Second}
+	var tests = []struct {
+		cfg      HealthConfig
+		status   int
+		problems int
+	}{
+		{HealthConfig{}, 200, 0},
+		{HealthConfig{Layout: &idgen.SnowflakeLayout, MinHeadroom: 24 * time.Hour}, 200, 0},
+		{HealthConfig{Layout: &jsSafe, MinHeadroom: 200 * 365 * 24 * time.Hour}, 503, 1},
+		{HealthConfig{Layout: &slow, MinHeadroom: 24 * time.Hour}, 200, 0},
+		{HealthConfig{Reference: func(context.Context) (time.Time, error) {
+			return time.Now(), nil
+		}}, 200, 0},
+		{HealthConfig{Reference: func(context.Context) (time.Time, error) {
+			return time.Now().Add(-time.Minute), nil
+		}}, 503, 1},
+		{HealthConfig{Reference: func(context.Context) (time.Time, error) {
+			return time.Time{}, errors.New("unreachable")
+		}}, 503, 1},
+		// The generator's clock is off, not the wall clock.
+		{HealthConfig{Clock: idgen.ClockFunc(func() int64 { return time.Now().Add(time.Minute).UnixNano() }),
+			Reference: func(context.Context) (time.Time, error) { return time.Now(), nil }}, 503, 1},
+		{HealthConfig{Overflows: func() int64 { overflows += 1000; return overflows },
+			MaxOverflowRate: 1}, 503, 1},
+		{HealthConfig{Checks: map[string]func(context.Context) error{
+			"db":   func(context.Context) error { return nil },
+			"etcd": func(context.Context) error { return errors.New("lease lost") },
+		}}, 503, 1},
+	}
+	for i, test := range tests {
+		rec := httptest.NewRecorder()
+		HealthHandler(test.cfg).ServeHTTP(rec, httptest.NewRequest("GET", "/healthz", nil))
+		var resp healthResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("TestHealthHandler %d: %v", i, err)
+		}
+		if rec.Code != test.status || len(resp.Problems) != test.problems {
+			t.Errorf("TestHealthHandler %d: got %d %s, expected %d with %d problems",
+				i, rec.Code, rec.Body, test.status, test.problems)
+		}
+	}
+}
+
+func TestHTTPDateReference(t *testing.T) {
+	date := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", date.Format(http.TimeFormat))
+	}))
+	defer srv.Close()
+	ref, err := HTTPDateReference(srv.Client(), srv.URL)(context.Background())
+	if err != nil || !ref.Equal(date) {
+		t.Errorf("TestHTTPDateReference: got %v (error %v), expected %v", ref, err, date)
+	}
+}
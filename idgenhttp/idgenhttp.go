@@ -0,0 +1,56 @@
+// Package idgenhttp serves ID generators over HTTP.
+package idgenhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/carloslenz/idgen"
+)
+
+// idResponse is the JSON body of Handler. IDs are also given as strings, since
+// JavaScript numbers lose precision above 2^53.
+type idResponse struct {
+	ID    int64  `json:"id"`
+	IDStr string `json:"id_str"`
+}
+
+// Handler returns an http.Handler generating one ID with gen per GET request. The ID is
+// written as plain text, or as JSON ({"id": 1, "id_str": "1"}) if the request accepts
+// application/json or has format=json in the query. Generator errors are reported with
+// 503 Service Unavailable, so clients retry (e.g. elsewhere) after clock or overflow
+// problems.
+func Handler(gen idgen.Interface) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := gen.NewIDs(1)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		if wantsJSON(r) {
+			writeJSON(w, http.StatusOK, idResponse{ID: id, IDStr: strconv.FormatInt(id, 10)})
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(strconv.FormatInt(id, 10) + "\n"))
+	})
+}
+
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" ||
+		strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,47 @@
+package idgenhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+)
+
+type broken struct{}
+
+func (broken) NewIDs(int64) (int64, error) { return 0, errors.New("broken ID generator is broken") }
+
+func TestHandler(t *testing.T) {
+	h := Handler(idgen.NewSequential())
+	var tests = []struct {
+		method, target, accept string
+		status                 int
+		contentType, body      string
+	}{
+		{"GET", "/", "", 200, "text/plain; charset=utf-8", "1\n"},
+		{"GET", "/?format=json", "", 200, "application/json", `{"id":2,"id_str":"2"}` + "\n"},
+		{"GET", "/", "application/json", 200, "application/json", `{"id":3,"id_str":"3"}` + "\n"},
+		{"POST", "/", "", 405, "text/plain; charset=utf-8", "Method Not Allowed\n"},
+		{"GET", "/", "", 200, "text/plain; charset=utf-8", "4\n"},
+	}
+	for i, test := range tests {
+		req := httptest.NewRequest(test.method, test.target, nil)
+		if test.accept != "" {
+			req.Header.Set("Accept", test.accept)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != test.status || rec.Header().Get("Content-Type") != test.contentType ||
+			rec.Body.String() != test.body {
+			t.Errorf("TestHandler %d: got %d %q %q, expected %d %q %q", i, rec.Code,
+				rec.Header().Get("Content-Type"), rec.Body, test.status, test.contentType, test.body)
+		}
+	}
+	rec := httptest.NewRecorder()
+	Handler(broken{}).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("TestHandler: got status %d, expected %d", rec.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,70 @@
+package idgenotel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestGenerator(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+	gen := New(idgen.NewOverflowChecker(2, idgen.NewSequential()), tracer, "")
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+	if v, err := gen.NewIDsContext(ctx, 3); err != nil || v != 3 {
+		t.Errorf("TestGenerator: got %v (error %v), expected 3", v, err)
+	}
+	parent.End()
+	if _, err := gen.NewIDs(1); !errors.Is(err, idgen.ErrOverflow) {
+		t.Errorf("TestGenerator: got error %v, expected %v", err, idgen.ErrOverflow)
+	}
+
+	spans := rec.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("TestGenerator: got %d spans, expected 3", len(spans))
+	}
+	var tests = []struct {
+		span    sdktrace.ReadOnlySpan
+		count   int64
+		outcome string
+		status  codes.Code
+	}{
+		{spans[0], 3, "ok", codes.Unset},
+		{spans[2], 1, "error", codes.Error},
+	}
+	for i, test := range tests {
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, kv := range test.span.Attributes() {
+			attrs[kv.Key] = kv.Value
+		}
+		if attrs["idgen.count"].AsInt64() != test.count ||
+			attrs["idgen.outcome"].AsString() != test.outcome ||
+			attrs["idgen.generator"].AsString() != "idgen.overflowChecker" ||
+			test.span.Status().Code != test.status {
+			t.Errorf("TestGenerator %d: got attributes %v, status %v", i, attrs, test.span.Status())
+		}
+	}
+	if spans[0].Parent().SpanID() != spans[1].SpanContext().SpanID() {
+		t.Errorf("TestGenerator: span is not a child of the context's span")
+	}
+}
+
+func TestGeneratorRange(t *testing.T) {
+	rec := tracetest.NewSpanRecorder()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)).Tracer("test")
+	gen := New(idgen.NewSonyflake(1), tracer, "sonyflake")
+	r, err := idgen.NewIDRange(gen, 3)
+	if err != nil || r.Step() != 1<<16 || r.Len() != 3 {
+		t.Errorf("TestGeneratorRange: got %+v (error %v), expected step %v", r, err, 1<<16)
+	}
+	if spans := rec.Ended(); len(spans) != 1 || spans[0].Name() != "idgen.NewIDs" {
+		t.Errorf("TestGeneratorRange: got spans %v, expected one", spans)
+	}
+}
@@ -0,0 +1,130 @@
+// Package idgenprom instruments ID generators with Prometheus metrics.
+package idgenprom
+
+import (
+	"errors"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Generator wraps an idgen.Interface, recording every NewIDs call. It is a
+// prometheus.Collector: register it to export the metrics, all labelled with the
+// generator name:
+//
+//	idgen_ids_issued_total             IDs successfully generated
+//	idgen_batch_size                   n of each NewIDs call
+//	idgen_errors_total{error=...}      failures: overflow, unsupported_count,
+//	                                   clock_moved_back, clock_skew, exhausted,
+//	                                   rate_limited, would_block, deadline, closed,
+//	                                   suspected_repeat, reservation_done or other
+//	idgen_call_duration_seconds        NewIDs latency, including lock waits
+//
+// Safe for concurrent use if the wrapped generator is.
+type Generator struct {
+	gen      idgen.Interface
+	issued   prometheus.Counter
+	batch    prometheus.Histogram
+	errors   *prometheus.CounterVec
+	duration prometheus.Histogram
+}
+
+// New wraps gen, labelling its metrics with generator=name.
+func New(gen idgen.Interface, name string) *Generator {
+	labels := prometheus.Labels{"generator": name}
+	return &Generator{
+		gen: gen,
+		issued: prometheus.NewCounter(prometheus.CounterOpts{
+			Name:        "idgen_ids_issued_total",
+			Help:        "IDs successfully generated.",
+			ConstLabels: labels,
+		}),
+		batch: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "idgen_batch_size",
+			Help:        "IDs requested per NewIDs call.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(1, 4, 8),
+		}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name:        "idgen_errors_total",
+			Help:        "Failed NewIDs calls by error.",
+			ConstLabels: labels,
+		}, []string{"error"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "idgen_call_duration_seconds",
+			Help:        "NewIDs latency, including lock and clock waits.",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(1e-7, 10, 8),
+		}),
+	}
+}
+
+// NewIDs implements idgen.Interface.
+func (g *Generator) NewIDs(n int64) (int64, error) {
+	start := time.Now()
+	id, err := g.gen.NewIDs(n)
+	g.observe(n, start, err)
+	return id, err
+}
+
+// NewIDRange implements idgen.RangeInterface, so batches of wrapped generators that are
+// not contiguous (e.g. Sonyflake) are described correctly.
+func (g *Generator) NewIDRange(n int64) (idgen.Range, error) {
+	start := time.Now()
+	r, err := idgen.NewIDRange(g.gen, n)
+	g.observe(n, start, err)
+	return r, err
+}
+
+func (g *Generator) observe(n int64, start time.Time, err error) {
+	g.duration.Observe(time.Since(start).Seconds())
+	g.batch.Observe(float64(n))
+	if err != nil {
+		g.errors.WithLabelValues(errorLabel(err)).Inc()
+		return
+	}
+	g.issued.Add(float64(n))
+}
+
+// errorLabel classifies err by the idgen sentinel it wraps.
+func errorLabel(err error) string {
+	for _, e := range []struct {
+		err   error
+		label string
+	}{
+		{idgen.ErrOverflow, "overflow"},
+		{idgen.ErrUnsupportedCount, "unsupported_count"},
+		{idgen.ErrClockMovedBack, "clock_moved_back"},
+		{idgen.ErrClockSkew, "clock_skew"},
+		{idgen.ErrExhausted, "exhausted"},
+		// Before would_block and deadline, which rate limited errors also wrap.
+		{idgen.ErrRateLimited, "rate_limited"},
+		{idgen.ErrWouldBlock, "would_block"},
+		{idgen.ErrDeadline, "deadline"},
+		{idgen.ErrClosed, "closed"},
+		{idgen.ErrSuspectedRepeat, "suspected_repeat"},
+		{idgen.ErrReservationDone, "reservation_done"},
+	} {
+		if errors.Is(err, e.err) {
+			return e.label
+		}
+	}
+	return "other"
+}
+
+// Describe implements prometheus.Collector.
+func (g *Generator) Describe(ch chan<- *prometheus.Desc) {
+	g.issued.Describe(ch)
+	g.batch.Describe(ch)
+	g.errors.Describe(ch)
+	g.duration.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (g *Generator) Collect(ch chan<- prometheus.Metric) {
+	g.issued.Collect(ch)
+	g.batch.Collect(ch)
+	g.errors.Collect(ch)
+	g.duration.Collect(ch)
+}
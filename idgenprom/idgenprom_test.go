@@ -0,0 +1,81 @@
+package idgenprom
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var errTest = errors.New("broken ID generator is broken")
+
+type broken struct{}
+
+func (broken) NewIDs(int64) (int64, error) { return 0, errTest }
+
+func TestGenerator(t *testing.T) {
+	gen := New(idgen.NewOverflowChecker(3, idgen.NewSequential()), "orders")
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(gen)
+	for _, n := range []int64{1, 4, 5} {
+		gen.NewIDs(n)
+	}
+	other := New(broken{}, "broken")
+	reg.MustRegister(other)
+	other.NewIDs(1)
+
+	expected := `
+# HELP idgen_errors_total Failed NewIDs calls by error.
+# TYPE idgen_errors_total counter
+idgen_errors_total{error="other",generator="broken"} 1
+idgen_errors_total{error="overflow",generator="orders"} 1
+# HELP idgen_ids_issued_total IDs successfully generated.
+# TYPE idgen_ids_issued_total counter
+idgen_ids_issued_total{generator="broken"} 0
+idgen_ids_issued_total{generator="orders"} 5
+`
+	err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"idgen_errors_total", "idgen_ids_issued_total")
+	if err != nil {
+		t.Errorf("TestGenerator: %v", err)
+	}
+	if n := testutil.CollectAndCount(gen, "idgen_batch_size", "idgen_call_duration_seconds"); n != 2 {
+		t.Errorf("TestGenerator: got %d histograms, expected 2", n)
+	}
+}
+
+func TestGeneratorRange(t *testing.T) {
+	gen := New(idgen.NewSonyflake(1), "sonyflake")
+	r, err := idgen.NewIDRange(gen, 3)
+	if err != nil || r.Step() != 1<<16 || r.Len() != 3 {
+		t.Errorf("TestGeneratorRange: got %+v (error %v), expected step %v", r, err, 1<<16)
+	}
+	if v := testutil.ToFloat64(gen.issued); v != 3 {
+		t.Errorf("TestGeneratorRange: got %v IDs issued, expected 3", v)
+	}
+}
+
+func TestErrorLabel(t *testing.T) {
+	var tests = []struct {
+		err      error
+		expected string
+	}{
+		{idgen.ErrClockSkew, "clock_skew"},
+		{fmt.Errorf("segment: %w", idgen.ErrExhausted), "exhausted"},
+		{fmt.Errorf("%w: %w", idgen.ErrRateLimited, idgen.ErrWouldBlock), "rate_limited"},
+		{idgen.ErrWouldBlock, "would_block"},
+		{idgen.ErrDeadline, "deadline"},
+		{idgen.ErrSuspectedRepeat, "suspected_repeat"},
+		{idgen.ErrReservationDone, "reservation_done"},
+		{errTest, "other"},
+	}
+	for i, test := range tests {
+		if got := errorLabel(test.err); got != test.expected {
+			t.Errorf("TestErrorLabel %d: got %q, expected %q", i, got, test.expected)
+		}
+	}
+}
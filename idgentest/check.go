@@ -0,0 +1,123 @@
+package idgentest
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+)
+
+// maxReported limits how many violations CheckError.Error lists.
+const maxReported = 10
+
+// Violation is a pair of IDs breaking a property: for uniqueness both are the repeated
+// ID, for monotonicity ID was generated right after Prev but is not greater.
+type Violation struct {
+	Prev, ID int64
+}
+
+// CheckError is returned by CheckUnique and CheckMonotonic when gen breaks the property.
+type CheckError struct {
+	Property   string
+	Violations []Violation
+}
+
+func (e *CheckError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "idgentest: %d %s violations:", len(e.Violations), e.Property)
+	for i, v := range e.Violations {
+		if i == maxReported {
+			b.WriteString(" ...")
+			break
+		}
+		if v.Prev == v.ID {
+			fmt.Fprintf(&b, " %d", v.ID)
+		} else {
+			fmt.Fprintf(&b, " %d>=%d", v.Prev, v.ID)
+		}
+	}
+	return b.String()
+}
+
+// generate calls gen.NewIDs(1) n times from GOMAXPROCS goroutines, and returns the IDs
+// each goroutine got, in order.
+func generate(gen idgen.Interface, n int) ([][]int64, error) {
+	workers := min(runtime.GOMAXPROCS(0), max(n, 1))
+	ids := make([][]int64, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		count := n / workers
+		if w < n%workers {
+			count++
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[w] = make([]int64, 0, count)
+			for range count {
+				id, err := gen.NewIDs(1)
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				ids[w] = append(ids[w], id)
+			}
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// CheckUnique generates n IDs with gen, concurrently, and returns a *CheckError listing
+// repeated IDs, or the first error returned by gen.
+func CheckUnique(gen idgen.Interface, n int) error {
+	ids, err := generate(gen, n)
+	if err != nil {
+		return err
+	}
+	all := slices.Concat(ids...)
+	slices.Sort(all)
+	var violations []Violation
+	for i := 1; i < len(all); i++ {
+		if all[i] == all[i-1] && (len(violations) == 0 || violations[len(violations)-1].ID != all[i]) {
+			violations = append(violations, Violation{all[i], all[i]})
+		}
+	}
+	if violations != nil {
+		return &CheckError{Property: "uniqueness", Violations: violations}
+	}
+	return nil
+}
+
+// CheckMonotonic generates n IDs with gen, concurrently, and returns a *CheckError
+// listing IDs not greater than the one the same goroutine got before, or the first error
+// returned by gen. Calls made by different goroutines are not ordered, so this catches
+// generators that go backwards (e.g. on counter wraparound) but not ones that merely
+// interleave.
+func CheckMonotonic(gen idgen.Interface, n int) error {
+	ids, err := generate(gen, n)
+	if err != nil {
+		return err
+	}
+	var violations []Violation
+	for _, seq := range ids {
+		for i := 1; i < len(seq); i++ {
+			if seq[i] <= seq[i-1] {
+				violations = append(violations, Violation{seq[i-1], seq[i]})
+			}
+		}
+	}
+	if violations != nil {
+		return &CheckError{Property: "monotonicity", Violations: violations}
+	}
+	return nil
+}
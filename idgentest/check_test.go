@@ -0,0 +1,49 @@
+package idgentest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestCheckUnique(t *testing.T) {
+	if err := CheckUnique(idgen.NewSequential(), 10000); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	gen, _ := idgen.NewDedup(idgen.NewSequential(), 1, 0)
+	if err := CheckUnique(gen, 100); err != nil {
+		t.Errorf("got error %v", err)
+	}
+
+	err := CheckUnique(idgen.NewConstant(7), 100)
+	var ce *CheckError
+	if !errors.As(err, &ce) || len(ce.Violations) != 1 || ce.Violations[0] != (Violation{7, 7}) {
+		t.Errorf("got error %v, expected one violation for 7", err)
+	}
+	if err := CheckUnique(Failing(errTest), 10); err != errTest {
+		t.Errorf("got error %v, expected %v", err, errTest)
+	}
+}
+
+func TestCheckMonotonic(t *testing.T) {
+	if err := CheckMonotonic(idgen.NewSequential(), 10000); err != nil {
+		t.Errorf("got error %v", err)
+	}
+	ids := make([]int64, 1000)
+	for i := range ids {
+		ids[i] = int64(len(ids) - i)
+	}
+	err := CheckMonotonic(Stub(ids...), len(ids))
+	var ce *CheckError
+	if !errors.As(err, &ce) || len(ce.Violations) < len(ids)/2 {
+		t.Errorf("got error %v, expected violations for a decreasing generator", err)
+	}
+}
+
+func TestCheckError(t *testing.T) {
+	err := &CheckError{Property: "monotonicity", Violations: []Violation{{3, 2}, {5, 5}}}
+	if s, expected := err.Error(), "idgentest: 2 monotonicity violations: 3>=2 5"; s != expected {
+		t.Errorf("got %q, expected %q", s, expected)
+	}
+}
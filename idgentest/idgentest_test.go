@@ -0,0 +1,58 @@
+package idgentest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+var _ idgen.Clock = (*Clock)(nil)
+
+func TestClock(t *testing.T) {
+	c := NewClock(Start)
+	if now := c.Now(); now != Start.UnixNano() {
+		t.Errorf("got %v, expected %v", now, Start.UnixNano())
+	}
+	c.Advance(time.Second)
+	if now := c.Time(); !now.Equal(Start.Add(time.Second)) {
+		t.Errorf("got %v, expected %v", now, Start.Add(time.Second))
+	}
+	c.Set(Start)
+	c.AutoAdvance(time.Millisecond)
+	for i := 0; i < 3; i++ {
+		expected := Start.Add(time.Duration(i) * time.Millisecond).UnixNano()
+		if now := c.Now(); now != expected {
+			t.Errorf("%d: got %v, expected %v", i, now, expected)
+		}
+	}
+	c.AutoAdvance(0)
+	if now, again := c.Now(), c.Now(); now != again {
+		t.Errorf("got %v, then %v", now, again)
+	}
+}
+
+func TestSnowflake(t *testing.T) {
+	gen, c := NewSnowflake(7)
+	ms := Start.UnixNano() / int64(time.Millisecond)
+	var tests = []struct {
+		count    int64
+		advance  time.Duration
+		expected int64
+	}{
+		{1, 0, ms<<22 | 7<<12},
+		{3, 0, ms<<22 | 7<<12 | 3},
+		{1, time.Millisecond, (ms+1)<<22 | 7<<12},
+	}
+	for i, test := range tests {
+		c.Advance(test.advance)
+		if v, err := gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("%d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+
+	gen, _ = NewSnowflake(7, idgen.WithEpoch(Start))
+	if v, err := gen.NewIDs(1); err != nil || v != 7<<12 {
+		t.Errorf("with epoch: got %v (error %v), expected %v", v, err, 7<<12)
+	}
+}
@@ -0,0 +1,111 @@
+package idgentest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+)
+
+// ErrExhausted is returned by Stub and Mock generators called more times than scripted.
+var ErrExhausted = errors.New("idgentest: no more scripted IDs")
+
+type stub struct {
+	mu  sync.Mutex
+	ids []int64
+}
+
+// Stub returns a generator handing out ids in order. NewIDs(n) consumes n of them and
+// returns the last, as real generators do; once they run out it fails with
+// ErrExhausted. Safe for concurrent use.
+func Stub(ids ...int64) idgen.Interface {
+	return &stub{ids: ids}
+}
+
+func (s *stub) NewIDs(n int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n < 1 {
+		return 0, fmt.Errorf("Stub.NewIDs(%d): %w", n, idgen.ErrUnsupportedCount)
+	}
+	if n > int64(len(s.ids)) {
+		return 0, ErrExhausted
+	}
+	id := s.ids[n-1]
+	s.ids = s.ids[n:]
+	return id, nil
+}
+
+type failing struct {
+	err error
+}
+
+// Failing returns a generator whose NewIDs always fails with err.
+func Failing(err error) idgen.Interface {
+	return failing{err}
+}
+
+func (f failing) NewIDs(int64) (int64, error) {
+	return 0, f.err
+}
+
+// Mock is a scriptable generator: each NewIDs call returns the next result queued with
+// Return or Fail, and is recorded so tests can inspect the requested counts. Once the
+// script runs out, calls fail with ErrExhausted. The zero value is ready to use and
+// safe for concurrent use.
+type Mock struct {
+	mu      sync.Mutex
+	results []mockResult
+	calls   []int64
+}
+
+type mockResult struct {
+	id  int64
+	err error
+}
+
+// Return queues one successful call per id, in order. It returns m for chaining.
+func (m *Mock) Return(ids ...int64) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		m.results = append(m.results, mockResult{id: id})
+	}
+	return m
+}
+
+// Fail queues a call failing with err. It returns m for chaining.
+func (m *Mock) Fail(err error) *Mock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results = append(m.results, mockResult{err: err})
+	return m
+}
+
+// NewIDs implements idgen.Interface.
+func (m *Mock) NewIDs(n int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, n)
+	if len(m.results) == 0 {
+		return 0, ErrExhausted
+	}
+	r := m.results[0]
+	m.results = m.results[1:]
+	return r.id, r.err
+}
+
+// Calls returns the count passed to each NewIDs call so far, in order.
+func (m *Mock) Calls() []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]int64(nil), m.calls...)
+}
+
+// Pending returns how many queued results were not consumed yet.
+func (m *Mock) Pending() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.results)
+}
@@ -0,0 +1,66 @@
+package idgentest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/carloslenz/idgen"
+)
+
+var (
+	_       idgen.Interface = (*Mock)(nil)
+	errTest                 = errors.New("test error")
+)
+
+func TestStub(t *testing.T) {
+	gen := Stub(10, 20, 30, 40)
+	var tests = []struct {
+		count, expected int64
+		err             error
+	}{
+		{1, 10, nil},
+		{2, 30, nil},
+		{0, 0, idgen.ErrUnsupportedCount},
+		{2, 0, ErrExhausted},
+		{1, 40, nil},
+		{1, 0, ErrExhausted},
+	}
+	for i, test := range tests {
+		if v, err := gen.NewIDs(test.count); v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("%d: got %v (error %v), expected %v (error %v)", i, v, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestFailing(t *testing.T) {
+	if v, err := Failing(errTest).NewIDs(1); v != 0 || err != errTest {
+		t.Errorf("got %v (error %v), expected error %v", v, err, errTest)
+	}
+}
+
+func TestMock(t *testing.T) {
+	var m Mock
+	m.Return(1, 2).Fail(errTest).Return(5)
+	var tests = []struct {
+		count, expected int64
+		err             error
+	}{
+		{1, 1, nil},
+		{3, 2, nil},
+		{1, 0, errTest},
+		{2, 5, nil},
+		{1, 0, ErrExhausted},
+	}
+	for i, test := range tests {
+		if v, err := m.NewIDs(test.count); v != test.expected || err != test.err {
+			t.Errorf("%d: got %v (error %v), expected %v (error %v)", i, v, err, test.expected, test.err)
+		}
+	}
+	if calls := m.Calls(); !slices.Equal(calls, []int64{1, 3, 1, 2, 1}) {
+		t.Errorf("got calls %v", calls)
+	}
+	if p := m.Pending(); p != 0 {
+		t.Errorf("got %d pending, expected 0", p)
+	}
+}
@@ -0,0 +1,82 @@
+package idgen
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+var (
+	// InstagramEpoch is the start of the timestamps of Instagram's IDs (2011-08-24
+	// 21:07:01.721 UTC).
+	InstagramEpoch = time.UnixMilli(1314220021721).UTC()
+	// InstagramLayout is the layout used by ShardedSnowflake (41/13/10): the nodeMask is
+	// the logical shard. It takes all 64 bits, so IDs become negative 2^40 Milliseconds
+	// (about 34 years) after the epoch; Validate rejects it for that reason, but
+	// ValidateUnsigned accepts it.
+	InstagramLayout = Layout{TimeBits: 41, NodeBits: 13, SeqBits: 10}
+)
+
+// InstagramShards is the number of logical shards of InstagramLayout.
+const InstagramShards = 1 << 13
+
+// ShardedSnowflake implements Instagram's ID scheme: each ID embeds the logical shard
+// derived from a key (e.g. a user or tenant), so it can be routed to the database shard
+// holding the key's data, and every shard has its own sequence of 1024 IDs per
+// Millisecond. Safe for concurrent use.
+type ShardedSnowflake struct {
+	mu     sync.RWMutex
+	shards map[int64]Interface
+	opts   []Option
+}
+
+// NewShardedSnowflake returns a ShardedSnowflake using InstagramLayout and
+// InstagramEpoch; opts are applied after the epoch. Options apply to every shard, so
+// WithTimestampStore and WithExpvar must not be used.
+func NewShardedSnowflake(opts ...Option) *ShardedSnowflake {
+	return &ShardedSnowflake{
+		shards: map[int64]Interface{},
+		opts:   append([]Option{WithEpoch(InstagramEpoch)}, opts...),
+	}
+}
+
+// Shard returns the logical shard of key, a hash of it modulo InstagramShards.
+func (s *ShardedSnowflake) Shard(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64() % InstagramShards)
+}
+
+// NewIDsForKey generates n IDs in the shard of key.
+func (s *ShardedSnowflake) NewIDsForKey(key string, n int64) (int64, error) {
+	return s.NewIDsForShard(s.Shard(key), n)
+}
+
+// NewIDsForShard generates n IDs in shard, which must be below InstagramShards.
+func (s *ShardedSnowflake) NewIDsForShard(shard, n int64) (int64, error) {
+	return s.shard(shard).NewIDs(n)
+}
+
+// ShardOf returns the logical shard of an ID generated by s.
+func (s *ShardedSnowflake) ShardOf(id int64) int64 {
+	return id >> InstagramLayout.SeqBits & (InstagramShards - 1)
+}
+
+func (s *ShardedSnowflake) shard(shard int64) Interface {
+	s.mu.RLock()
+	gen := s.shards[shard]
+	s.mu.RUnlock()
+	if gen != nil {
+		return gen
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if gen = s.shards[shard]; gen == nil {
+		// Out of range shards are reported by NewIDs, and not kept.
+		gen = InstagramLayout.NewSnowflake(shard, s.opts...)
+		if shard >= 0 && shard < InstagramShards {
+			s.shards[shard] = gen
+		}
+	}
+	return gen
+}
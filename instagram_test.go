@@ -0,0 +1,42 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShardedSnowflake(t *testing.T) {
+	t.Parallel()
+	now := InstagramEpoch.Add(5 * time.Millisecond).UnixNano()
+	gen := NewShardedSnowflake(WithClock(ClockFunc(func() int64 { return now })))
+	shard := gen.Shard("user-42")
+	if shard < 0 || shard >= InstagramShards || shard != gen.Shard("user-42") {
+		t.Fatalf("TestShardedSnowflake: got shard %v", shard)
+	}
+	var tests = []struct {
+		key      string
+		count    int64
+		expected int64
+	}{
+		{"user-42", 1, 5<<23 | shard<<10},
+		{"user-42", 3, 5<<23 | shard<<10 | 3},
+		{"user-42", 1, 5<<23 | shard<<10 | 4},
+		{"other", 1, 5<<23 | gen.Shard("other")<<10},
+	}
+	for i, test := range tests {
+		v, err := gen.NewIDsForKey(test.key, test.count)
+		if err != nil || v != test.expected {
+			t.Errorf("TestShardedSnowflake %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+		if s := gen.ShardOf(v); s != gen.Shard(test.key) {
+			t.Errorf("TestShardedSnowflake %d: got shard %v, expected %v", i, s, gen.Shard(test.key))
+		}
+	}
+	if v, err := gen.NewIDsForShard(InstagramShards, 1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("TestShardedSnowflake: got %v (error %v), expected %v", v, err, ErrOverflow)
+	}
+	if ts, node, seq := InstagramLayout.Decompose(5<<23|7<<10|9, WithEpoch(InstagramEpoch)); !ts.Equal(InstagramEpoch.Add(5*time.Millisecond)) || node != 7 || seq != 9 {
+		t.Errorf("TestShardedSnowflake: got %v, %v, %v", ts, node, seq)
+	}
+}
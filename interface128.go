@@ -0,0 +1,112 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// Interface128 is implemented by generators of 128-bit IDs (UUIDs, ULIDs, Flakes), which
+// do not fit Interface. Like Interface, NewIDs returns the last ID of a batch of n, but
+// most 128-bit generators only support n=1. KSUIDs (160 bits) do not fit either.
+type Interface128 interface {
+	NewIDs(n int64) ([16]byte, error)
+}
+
+type func128[T ~[16]byte] func() (T, error)
+
+// Func128 adapts a function returning 128-bit IDs (e.g. NewUUIDv7, or the New method of
+// a FlakeGenerator or MonotonicULID) to Interface128. Its NewIDs method only accepts
+// n=1.
+func Func128[T ~[16]byte](f func() (T, error)) Interface128 {
+	return func128[T](f)
+}
+
+func (f func128[T]) NewIDs(n int64) ([16]byte, error) {
+	if n != 1 {
+		return [16]byte{}, unsupportedCount128(f, n)
+	}
+	id, err := f()
+	return [16]byte(id), err
+}
+
+// NewIDs implements Interface128. Only n=1 is supported.
+func (g *FlakeGenerator) NewIDs(n int64) ([16]byte, error) {
+	if n != 1 {
+		return [16]byte{}, unsupportedCount128(g, n)
+	}
+	f, err := g.New()
+	return [16]byte(f), err
+}
+
+// NewIDs implements Interface128. Only n=1 is supported.
+func (m *MonotonicULID) NewIDs(n int64) ([16]byte, error) {
+	if n != 1 {
+		return [16]byte{}, unsupportedCount128(m, n)
+	}
+	ulid, err := m.New()
+	return [16]byte(ulid), err
+}
+
+type widened struct {
+	gen Interface
+}
+
+// Widen adapts gen to Interface128: IDs are sign-extended to 128 bits, big-endian, so
+// non-negative IDs sort the same as bytes. Batches are passed through to gen.
+func Widen(gen Interface) Interface128 {
+	return widened{gen}
+}
+
+func (w widened) NewIDs(n int64) ([16]byte, error) {
+	var id [16]byte
+	v, err := w.gen.NewIDs(n)
+	if err != nil {
+		return id, err
+	}
+	binary.BigEndian.PutUint64(id[:8], uint64(v>>63))
+	binary.BigEndian.PutUint64(id[8:], uint64(v))
+	return id, nil
+}
+
+type narrowed struct {
+	gen  Interface128
+	name string
+}
+
+// Narrow adapts gen to Interface. NewIDs fails with ErrOverflow if an ID does not fit in
+// an int64, i.e. its 65 most significant bits are not all equal; so it only makes sense
+// for generators with a narrow value range, such as Widen's. Batches are passed through
+// to gen.
+func Narrow(gen Interface128) Interface {
+	return narrowed{gen: gen, name: fmt.Sprintf("%T", gen)}
+}
+
+func (n narrowed) NewIDs(count int64) (int64, error) {
+	id, err := n.gen.NewIDs(count)
+	if err != nil {
+		return 0, err
+	}
+	hi, lo := binary.BigEndian.Uint64(id[:8]), binary.BigEndian.Uint64(id[8:])
+	if diff := hi ^ uint64(int64(lo)>>63); diff != 0 {
+		return 0, &Error{
+			Generator: n.name,
+			Count:     count,
+			Value:     int64(lo),
+			Bit:       64 + bits.TrailingZeros64(diff),
+			Err:       ErrOverflow,
+		}
+	}
+	return int64(lo), nil
+}
+
+// unsupportedCount128 is like unsupportedCount, for Interface128 generators.
+func unsupportedCount128(gen Interface128, n int64) error {
+	return &Error{
+		Generator: fmt.Sprintf("%T", gen),
+		Count:     n,
+		Value:     n,
+		Bit:       -1,
+		Err:       ErrUnsupportedCount,
+	}
+}
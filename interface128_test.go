@@ -0,0 +1,70 @@
+package idgen
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+)
+
+var (
+	_ Interface128 = (*FlakeGenerator)(nil)
+	_ Interface128 = (*MonotonicULID)(nil)
+)
+
+func TestFunc128(t *testing.T) {
+	t.Parallel()
+	m := NewMonotonicULID(rand.Reader)
+	flakes, _ := NewFlakeGenerator(1)
+	for i, gen := range []Interface128{Func128(NewUUIDv7), Func128(m.New), m, flakes} {
+		a, err := gen.NewIDs(1)
+		if err != nil {
+			t.Errorf("TestFunc128 %d: got error %v", i, err)
+			continue
+		}
+		if b, err := gen.NewIDs(1); err != nil || a == b {
+			t.Errorf("TestFunc128 %d: got %x twice (error %v)", i, a, err)
+		}
+		if _, err := gen.NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+			t.Errorf("TestFunc128 %d: got error %v, expected %v", i, err, ErrUnsupportedCount)
+		}
+	}
+}
+
+func TestWidenNarrow(t *testing.T) {
+	t.Parallel()
+	gen := Widen(NewNegSequential())
+	a, _ := gen.NewIDs(1)
+	expected := [16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x80, 0, 0, 0, 0, 0, 0, 1}
+	if a != expected {
+		t.Errorf("TestWidenNarrow: got %x, expected %x", a, expected)
+	}
+	seq := Widen(NewSequential())
+	b, _ := seq.NewIDs(3)
+	c, _ := seq.NewIDs(1)
+	if b[15] != 3 || bytes.Compare(b[:], c[:]) >= 0 {
+		t.Errorf("TestWidenNarrow: got %x then %x", b, c)
+	}
+
+	var tests = []struct {
+		gen      Interface128
+		expected int64
+		bit      int
+	}{
+		{Widen(NewConstant(-5)), -5, -1},
+		{Widen(NewConstant(1 << 62)), 1 << 62, -1},
+		{Func128(func() ([16]byte, error) { return [16]byte{15: 1, 8: 0x80}, nil }), 0, 63},
+		{Func128(func() ([16]byte, error) { return [16]byte{6: 2}, nil }), 0, 73},
+		{Func128(NewUUIDv7), 0, 64},
+	}
+	for i, test := range tests {
+		v, err := Narrow(test.gen).NewIDs(1)
+		var e *Error
+		switch {
+		case test.bit < 0 && (err != nil || v != test.expected):
+			t.Errorf("TestWidenNarrow %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		case test.bit >= 0 && (!errors.As(err, &e) || e.Err != ErrOverflow || e.Bit < test.bit):
+			t.Errorf("TestWidenNarrow %d: got %v (error %v), expected overflow at bit %d", i, v, err, test.bit)
+		}
+	}
+}
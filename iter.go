@@ -0,0 +1,28 @@
+package idgen
+
+import "iter"
+
+// All returns an endless iterator over IDs generated one at a time by gen. It stops after
+// yielding the first error (with a zero ID), or when the caller breaks out of the loop.
+func All(gen Interface) iter.Seq2[int64, error] {
+	return AllBatch(gen, 1)
+}
+
+// AllBatch is like All, requesting n IDs per NewIDs call (see NewIDRange) and yielding
+// them one by one. Unconsumed IDs of the last batch are lost when the loop is broken.
+func AllBatch(gen Interface, n int64) iter.Seq2[int64, error] {
+	return func(yield func(int64, error) bool) {
+		for {
+			r, err := NewIDRange(gen, n)
+			if err != nil {
+				yield(0, err)
+				return
+			}
+			for id := range r.All() {
+				if !yield(id, nil) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package idgen
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		gen      Interface
+		n        int64
+		expected []int64
+		err      error
+	}{
+		{NewSequential(), 1, []int64{1, 2, 3, 4, 5}, nil},
+		{NewSequential(), 2, []int64{1, 2, 3, 4, 5}, nil},
+		{NewOverflowChecker(2, NewSequential()), 1, []int64{1, 2, 3, 0}, ErrOverflow},
+		{broken{errTest}, 3, []int64{0}, errTest},
+		{NewSequential(), 0, []int64{0}, ErrUnsupportedCount},
+	}
+	for i, test := range tests {
+		var ids []int64
+		var err error
+		for id, e := range AllBatch(test.gen, test.n) {
+			ids, err = append(ids, id), e
+			if e != nil || len(ids) == 5 {
+				break
+			}
+		}
+		if !slices.Equal(ids, test.expected) || !errors.Is(err, test.err) {
+			t.Errorf("TestAll %d: got %v (error %v), expected %v (error %v)",
+				i, ids, err, test.expected, test.err)
+		}
+	}
+	var ids []int64
+	for id := range All(NewSequential()) {
+		if ids = append(ids, id); len(ids) == 3 {
+			break
+		}
+	}
+	if !slices.Equal(ids, []int64{1, 2, 3}) {
+		t.Errorf("TestAll: got %v, expected [1 2 3]", ids)
+	}
+}
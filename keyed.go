@@ -0,0 +1,94 @@
+package idgen
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeyedSequential maintains an independent sequential counter per key (e.g. tenant,
+// topic or document), created on first use. Safe for concurrent use.
+type KeyedSequential struct {
+	mu       sync.RWMutex
+	counters map[string]*keyedCounter
+	clock    Clock
+}
+
+type keyedCounter struct {
+	sequential
+	// lastUsed in Unix Nanoseconds, for EvictIdle.
+	lastUsed atomic.Int64
+}
+
+// NewKeyedSequential returns a KeyedSequential with no keys.
+func NewKeyedSequential() *KeyedSequential {
+	return &KeyedSequential{counters: map[string]*keyedCounter{}, clock: SystemClock}
+}
+
+// NewIDs generates n IDs for key, like NewSequential: the first ID of every key is 1.
+func (k *KeyedSequential) NewIDs(key string, n int64) (int64, error) {
+	c := k.counter(key)
+	c.lastUsed.Store(k.clock.Now())
+	return c.NewIDs(n)
+}
+
+// Key returns a generator bound to key.
+func (k *KeyedSequential) Key(key string) Interface {
+	return keyedGen{k, key}
+}
+
+func (k *KeyedSequential) counter(key string) *keyedCounter {
+	k.mu.RLock()
+	c := k.counters[key]
+	k.mu.RUnlock()
+	if c != nil {
+		return c
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if c = k.counters[key]; c == nil {
+		c = &keyedCounter{}
+		k.counters[key] = c
+	}
+	return c
+}
+
+// Len returns the number of keys.
+func (k *KeyedSequential) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.counters)
+}
+
+// Evict forgets key, so its counter restarts from 1 on next use. Only evict keys whose
+// IDs can no longer clash (e.g. deleted documents).
+func (k *KeyedSequential) Evict(key string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.counters, key)
+}
+
+// EvictIdle forgets keys unused for longer than idle, returning how many were evicted.
+// The same caveats as Evict apply.
+func (k *KeyedSequential) EvictIdle(idle time.Duration) int {
+	limit := k.clock.Now() - int64(idle)
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var evicted int
+	for key, c := range k.counters {
+		if c.lastUsed.Load() < limit {
+			delete(k.counters, key)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+type keyedGen struct {
+	k   *KeyedSequential
+	key string
+}
+
+func (g keyedGen) NewIDs(n int64) (int64, error) {
+	return g.k.NewIDs(g.key, n)
+}
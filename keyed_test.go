@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKeyedSequential(t *testing.T) {
+	t.Parallel()
+	now := int64(0)
+	k := NewKeyedSequential()
+	k.clock = ClockFunc(func() int64 { return now })
+	b := k.Key("b")
+	var tests = []struct {
+		key             string
+		count, expected int64
+	}{
+		{"a", 1, 1},
+		{"a", 2, 3},
+		{"b", 1, 1},
+		{"a", 1, 4},
+		{"b", 5, 6},
+	}
+	for i, test := range tests {
+		var v int64
+		var err error
+		if test.key == "b" {
+			v, err = b.NewIDs(test.count)
+		} else {
+			v, err = k.NewIDs(test.key, test.count)
+		}
+		if err != nil || v != test.expected {
+			t.Errorf("TestKeyedSequential %d: got %v (error %v), expected %v",
+				i, v, err, test.expected)
+		}
+	}
+
+	now = int64(time.Minute)
+	k.NewIDs("c", 1)
+	now += int64(time.Second)
+	if n := k.EvictIdle(30 * time.Second); n != 2 || k.Len() != 1 {
+		t.Errorf("TestKeyedSequential: evicted %d, %d left, expected 2 and 1", n, k.Len())
+	}
+	k.Evict("c")
+	if v, _ := k.NewIDs("c", 1); v != 1 || k.Len() != 1 {
+		t.Errorf("TestKeyedSequential: got %v with %d keys after Evict, expected 1 with 1", v, k.Len())
+	}
+}
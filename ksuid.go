@@ -0,0 +1,85 @@
+package idgen
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+)
+
+// KSUID is a K-Sortable Unique IDentifier, compatible with Segment's: 32 bits of
+// timestamp (Seconds since KSUIDEpoch) followed by 128 random bits, big-endian.
+type KSUID [20]byte
+
+// KSUIDEpoch is the Unix time (in Seconds) KSUID timestamps are counted from.
+const KSUIDEpoch = 1400000000
+
+const (
+	base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidLen is the length of the string representation.
+	ksuidLen = 27
+)
+
+// NewKSUID produces a KSUID with the current time and randomness read from entropy.
+func NewKSUID(entropy io.Reader) (KSUID, error) {
+	var ksuid KSUID
+	if _, err := io.ReadFull(entropy, ksuid[4:]); err != nil {
+		return ksuid, err
+	}
+	ts := uint32(time.Now().Unix() - KSUIDEpoch)
+	ksuid[0], ksuid[1], ksuid[2], ksuid[3] = byte(ts>>24), byte(ts>>16), byte(ts>>8), byte(ts)
+	return ksuid, nil
+}
+
+// ParseKSUID decodes the base62 representation produced by KSUID.String.
+func ParseKSUID(s string) (KSUID, error) {
+	var ksuid KSUID
+	if len(s) != ksuidLen {
+		return ksuid, fmt.Errorf("ParseKSUID(%q): expected length %d, got %d", s, ksuidLen, len(s))
+	}
+	v := new(big.Int)
+	b62 := big.NewInt(62)
+	for i := 0; i < len(s); i++ {
+		d := digit62(s[i])
+		if d < 0 {
+			return ksuid, fmt.Errorf("ParseKSUID(%q): invalid character %q", s, s[i])
+		}
+		v.Mul(v, b62).Add(v, big.NewInt(int64(d)))
+	}
+	if v.BitLen() > len(ksuid)*8 {
+		return ksuid, fmt.Errorf("ParseKSUID(%q): overflow", s)
+	}
+	v.FillBytes(ksuid[:])
+	return ksuid, nil
+}
+
+// Time returns the KSUID timestamp.
+func (ksuid KSUID) Time() time.Time {
+	ts := uint32(ksuid[0])<<24 | uint32(ksuid[1])<<16 | uint32(ksuid[2])<<8 | uint32(ksuid[3])
+	return time.Unix(int64(ts)+KSUIDEpoch, 0)
+}
+
+// String returns KSUID in canonical format (27 base62 characters).
+func (ksuid KSUID) String() string {
+	var s [ksuidLen]byte
+	v := new(big.Int).SetBytes(ksuid[:])
+	b62 := big.NewInt(62)
+	m := new(big.Int)
+	for i := len(s) - 1; i >= 0; i-- {
+		v.DivMod(v, b62, m)
+		s[i] = base62[m.Int64()]
+	}
+	return string(s[:])
+}
+
+func digit62(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'A' <= c && c <= 'Z':
+		return int(c-'A') + 10
+	case 'a' <= c && c <= 'z':
+		return int(c-'a') + 36
+	}
+	return -1
+}
@@ -0,0 +1,54 @@
+package idgen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestKSUID(t *testing.T) {
+	ids := []struct {
+		v KSUID
+		s string
+	}{
+		{KSUID{}, "000000000000000000000000000"},
+		{[...]byte{0x06, 0x69, 0xf7, 0xef, 0xb5, 0xa1, 0xcd, 0x34, 0xb5, 0xf9, 0x9d, 0x11,
+			0x54, 0xfb, 0x68, 0x53, 0x34, 0x5c, 0x97, 0x35}, "0ujtsYcgvSTl8PAuAdqWYSMnLOv"},
+		{[...]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, "aWgEPTl1tmebfsQzFP4bxwgy80V"},
+	}
+	for i, id := range ids {
+		s := id.v.String()
+		v, err := ParseKSUID(id.s)
+		switch {
+		case s != id.s:
+			t.Errorf("%d: repr, got %s, expected %s", i, s, id.s)
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case v != id.v:
+			t.Errorf("%d: parse, got %v, expected %v", i, v, id.v)
+		}
+	}
+	if ts := ids[1].v.Time(); !ts.Equal(time.Unix(1507608047, 0)) {
+		t.Errorf("time, got %v", ts)
+	}
+	for _, s := range []string{"", "0ujtsYcgvSTl8PAuAdqWYSMnLO", "0ujtsYcgvSTl8PAuAdqWYSMnLO-",
+		"aWgEPTl1tmebfsQzFP4bxwgy80W"} {
+		if v, err := ParseKSUID(s); err == nil {
+			t.Errorf("ParseKSUID(%q): expected error, got %v", s, v)
+		}
+	}
+}
+
+func TestNewKSUID(t *testing.T) {
+	random := bytes.Repeat([]byte{0xab}, 16)
+	ksuid, err := NewKSUID(bytes.NewReader(random))
+	switch {
+	case err != nil:
+		t.Errorf("got error %q", err)
+	case time.Since(ksuid.Time()) > time.Second:
+		t.Errorf("time, got %v", ksuid.Time())
+	case !bytes.Equal(ksuid[4:], random):
+		t.Errorf("entropy, got %v, expected %v", ksuid[4:], random)
+	}
+}
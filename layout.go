@@ -0,0 +1,221 @@
+package idgen
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+// Layout is the bit allocation of Snowflake-like IDs: from the most significant bits, a
+// timestamp (Units since Unix epoch, unless WithEpoch is used), a nodeMask and a
+// sequence number.
+type Layout struct {
+	TimeBits, NodeBits, SeqBits byte
+	// Unit is the timestamp resolution, Millisecond if zero.
+	Unit time.Duration
+}
+
+var (
+	// SnowflakeLayout is the layout used by NewSnowflake (41/10/12).
+	SnowflakeLayout = Layout{TimeBits: 41, NodeBits: 10, SeqBits: 12}
+	// JSSafeLayout is the layout used by NewJSSafeSnowflake: 32 bits of Seconds (up until
+	// year 2106), 5 bits of nodeMask and 16 bits of sequence, 53 bits in total.
+	JSSafeLayout = Layout{TimeBits: 32, NodeBits: 5, SeqBits: 16, Unit: time.Second}
+	// Snowflake10msLayout counts 10 Milliseconds (like Sonyflake) in 39 bits, lasting 174
+	// years instead of SnowflakeLayout's 69, with up to 16384 IDs per unit (1.6M/s).
+	Snowflake10msLayout = Layout{TimeBits: 39, NodeBits: 10, SeqBits: 14, Unit: 10 * time.Millisecond}
+	// SnowflakeSecondLayout counts Seconds in 33 bits, lasting 272 years, with up to 2^20
+	// IDs per Second.
+	SnowflakeSecondLayout = Layout{TimeBits: 33, NodeBits: 10, SeqBits: 20, Unit: time.Second}
+	// SnowflakeMicroLayout is the layout used by NewMicroSnowflake: 51 bits of
+	// Microseconds (71 years), 8 bits of nodeMask and 4 bits of sequence.
+	SnowflakeMicroLayout = Layout{TimeBits: 51, NodeBits: 8, SeqBits: 4, Unit: time.Microsecond}
+)
+
+// NewJSSafeSnowflake returns a Snowflake generator using JSSafeLayout, so IDs are at most
+// 2^53-1 and survive conversion to JavaScript's Number (e.g. in JSON) without loss of
+// precision. It supports up to 32 generating nodes, each generating up to 65536 IDs per
+// Second. Safe for concurrent use.
+func NewJSSafeSnowflake(nodeMask int64, opts ...Option) Interface {
+	return JSSafeLayout.NewSnowflake(nodeMask, opts...)
+}
+
+// NewMicroSnowflake returns a Snowflake generator using SnowflakeMicroLayout. Each of up
+// to 256 nodes generates up to 16 IDs per Microsecond (16M per Second), which suits
+// workloads issuing steady streams of IDs, where Millisecond buckets overflow at the
+// start of every Millisecond. Use WithEpoch to extend the lifetime past 2041. Safe for
+// concurrent use.
+func NewMicroSnowflake(nodeMask int64, opts ...Option) Interface {
+	return SnowflakeMicroLayout.NewSnowflake(nodeMask, opts...)
+}
+
+// NewSnowflakeLayout returns a Layout, checking that all fields fit in 63 bits (so IDs
+// are never negative). More NodeBits allow more generating nodes, more SeqBits allow
+// more IDs per Millisecond and more TimeBits extend the lifetime.
+func NewSnowflakeLayout(timeBits, nodeBits, seqBits byte) (Layout, error) {
+	l := Layout{TimeBits: timeBits, NodeBits: nodeBits, SeqBits: seqBits}
+	if sum := int(timeBits) + int(nodeBits) + int(seqBits); sum > 63 {
+		return l, fmt.Errorf("NewSnowflakeLayout(%d, %d, %d): %d bits, at most 63 allowed",
+			timeBits, nodeBits, seqBits, sum)
+	}
+	if timeBits == 0 || seqBits == 0 {
+		return l, fmt.Errorf("NewSnowflakeLayout(%d, %d, %d): time and sequence bits are required",
+			timeBits, nodeBits, seqBits)
+	}
+	return l, nil
+}
+
+// ConfigError reports an invalid generator configuration, detected before generating.
+type ConfigError struct {
+	// Field is the offending setting, e.g. "nodeMask" or "epoch".
+	Field string
+	// Value is the offending value, formatted.
+	Value string
+	// Reason explains why Value is invalid.
+	Reason string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("idgen: invalid %s %s: %s", e.Field, e.Value, e.Reason)
+}
+
+// Validate checks a Snowflake configuration, so mistakes are reported on startup instead
+// of on the first NewIDs call: the bit widths must fit in 63 bits, nodeMask must fit in
+// NodeBits, and the clock must be between the epoch and the end of the layout's lifetime.
+// Errors are of type *ConfigError.
+func (l Layout) Validate(nodeMask int64, opts ...Option) error {
+	return l.validate(nodeMask, 63, opts)
+}
+
+// ValidateUnsigned is like Validate, for NewUnsignedSnowflake: the bit widths may add up
+// to 64.
+func (l Layout) ValidateUnsigned(nodeMask int64, opts ...Option) error {
+	return l.validate(nodeMask, 64, opts)
+}
+
+func (l Layout) validate(nodeMask int64, maxBits int, opts []Option) error {
+	if sum := int(l.TimeBits) + int(l.NodeBits) + int(l.SeqBits); sum > maxBits {
+		return &ConfigError{"layout", fmt.Sprintf("%d/%d/%d", l.TimeBits, l.NodeBits, l.SeqBits),
+			fmt.Sprintf("%d bits, at most %d allowed", sum, maxBits)}
+	}
+	if l.TimeBits == 0 || l.SeqBits == 0 {
+		return &ConfigError{"layout", fmt.Sprintf("%d/%d/%d", l.TimeBits, l.NodeBits, l.SeqBits),
+			"time and sequence bits are required"}
+	}
+	if l.Unit < 0 {
+		return &ConfigError{"unit", l.Unit.String(), "must be positive"}
+	}
+	if nodeMask < 0 || nodeMask>>l.NodeBits != 0 {
+		return &ConfigError{"nodeMask", fmt.Sprint(nodeMask),
+			fmt.Sprintf("does not fit in %d bits", l.NodeBits)}
+	}
+	o := newOptions(opts)
+	now := o.clock.Now()
+	if now < o.epoch {
+		return &ConfigError{"epoch", time.Unix(0, o.epoch).UTC().Format(time.RFC3339),
+			"is in the future"}
+	}
+	if (now-o.epoch)/int64(l.unit())>>l.TimeBits != 0 {
+		return &ConfigError{"epoch", time.Unix(0, o.epoch).UTC().Format(time.RFC3339),
+			fmt.Sprintf("too old for %d time bits", l.TimeBits)}
+	}
+	return nil
+}
+
+// NewSnowflakeChecked is like NewSnowflake, returning the error from Validate instead of
+// failing on NewIDs.
+func (l Layout) NewSnowflakeChecked(nodeMask int64, opts ...Option) (Interface, error) {
+	if err := l.Validate(nodeMask, opts...); err != nil {
+		return nil, err
+	}
+	return l.NewSnowflake(nodeMask, opts...), nil
+}
+
+// NewSnowflake returns an ID generator that follows Twitter's Snowflake algorithm, using
+// the layout's bit widths. Configuration errors are only reported by NewIDs; see
+// NewSnowflakeChecked. Safe for concurrent use.
+func (l Layout) NewSnowflake(nodeMask int64, opts ...Option) Interface {
+	o := newOptions(opts)
+	seq := &sequential{}
+	// Persisted timestamps are shifted like generated ones, reserving at least one unit.
+	ahead := int64(o.storeInterval / l.unit())
+	if ahead < 1 {
+		ahead = 1
+	}
+	var stats *snowflakeStats
+	if o.expvar != "" {
+		stats = newSnowflakeStats(o.expvar, l, o.epoch)
+	}
+	// Regressions are measured in whole units, shifted like timestamps.
+	tolerance := uint64((max(o.tolerance, 0)+l.unit()-1)/l.unit()) << (l.NodeBits + l.SeqBits)
+	return &snowflake{
+		tolerance: tolerance,
+		maxWait:   o.maxWait,
+		monitor:   o.monitor,
+		stats:     stats,
+		store:     o.store,
+		ahead:     ahead << (l.NodeBits + l.SeqBits),
+		// Timestamps are never negative, so the first one is always new (even at epoch).
+		lastTimestamp: -1,
+		// Needed to reset when a new timestamp is entered.
+		sequential: seq,
+		// Least significant bits: only one that accepts counter > 1.
+		seqChecker: NewOverflowChecker(l.SeqBits, seq),
+		seqSize:    1 << l.SeqBits,
+		wait:       o.wait,
+		waitClock:  o.waitClock,
+		constant: shifted{
+			gen:  NewOverflowChecker(l.NodeBits, constant(nodeMask)),
+			bits: l.SeqBits,
+		},
+		tstamp: shifted{
+			gen: NewOverflowChecker(l.TimeBits, tstamp{
+				epoch: o.epoch,
+				unit:  int64(l.unit()),
+				clock: o.clock,
+			}),
+			bits: l.NodeBits + l.SeqBits,
+		},
+	}
+}
+
+// Decompose splits an ID generated with the layout into its timestamp, nodeMask and
+// sequence number. The options must match the ones used for generation. IDs from
+// NewUnsignedSnowflake are accepted converted with int64(id).
+func (l Layout) Decompose(id int64, opts ...Option) (ts time.Time, node int64, seq int64) {
+	o := newOptions(opts)
+	units := uint64(id) >> (l.NodeBits + l.SeqBits)
+	// units*unit Nanoseconds may not fit in 64 bits (nor, for absurd units, the Seconds).
+	hi, lo := bits.Mul64(units, uint64(l.unit()))
+	hi = min(hi, uint64(time.Second)-1)
+	secs, nanos := bits.Div64(hi, lo, uint64(time.Second))
+	return time.Unix(int64(secs), int64(nanos)).Add(time.Duration(o.epoch)),
+		id >> l.SeqBits & (1<<l.NodeBits - 1), id & (1<<l.SeqBits - 1)
+}
+
+// Lifetime returns how long after the epoch the layout's timestamps fit in TimeBits,
+// capped at the largest Duration (292 years).
+func (l Layout) Lifetime() time.Duration {
+	if l.TimeBits >= 63 || int64(1)<<l.TimeBits > int64(1<<63-1)/int64(l.unit()) {
+		return time.Duration(1<<63 - 1)
+	}
+	return time.Duration(1<<l.TimeBits) * l.unit()
+}
+
+// PerSecond returns how many IDs per Second each node can generate, rounded down (so 0
+// for less than one, with a Unit longer than a Second and few sequence bits).
+func (l Layout) PerSecond() int64 {
+	hi, lo := bits.Mul64(1<<l.SeqBits, uint64(time.Second))
+	if hi >= uint64(l.unit()) {
+		return 1<<63 - 1
+	}
+	perSecond, _ := bits.Div64(hi, lo, uint64(l.unit()))
+	return int64(min(perSecond, 1<<63-1))
+}
+
+func (l Layout) unit() time.Duration {
+	if l.Unit == 0 {
+		return time.Millisecond
+	}
+	return l.Unit
+}
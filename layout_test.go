@@ -0,0 +1,234 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewSnowflakeLayout(t *testing.T) {
+	var tests = []struct {
+		timeBits, nodeBits, seqBits byte
+		expectError                 bool
+	}{
+		{41, 10, 12, false},
+		{41, 0, 22, false},
+		{42, 10, 12, true},
+		{0, 10, 12, true},
+		{41, 10, 0, true},
+	}
+	for i, test := range tests {
+		_, err := NewSnowflakeLayout(test.timeBits, test.nodeBits, test.seqBits)
+		if (err != nil) != test.expectError {
+			t.Errorf("TestNewSnowflakeLayout %d: got error %v, expected error %v",
+				i, err, test.expectError)
+		}
+	}
+}
+
+func TestLayout(t *testing.T) {
+	l, err := NewSnowflakeLayout(41, 4, 18)
+	if err != nil {
+		t.Fatalf("TestLayout: got error %q", err)
+	}
+	gen := l.NewSnowflake(15)
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NewIDs(1 << 17)
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("TestLayout: got error %q", err)
+	}
+	ts, node, seq := l.Decompose(id)
+	switch {
+	case ts.Before(before) || ts.After(after):
+		t.Errorf("TestLayout: got time %v, expected between %v and %v", ts, before, after)
+	case node != 15:
+		t.Errorf("TestLayout: got node %v, expected 15", node)
+	case seq != 1<<17-1:
+		t.Errorf("TestLayout: got sequence %v, expected %v", seq, 1<<17-1)
+	}
+	if _, err := l.NewSnowflake(16).NewIDs(1); err == nil {
+		t.Errorf("TestLayout: expected node overflow error")
+	}
+}
+
+func TestWithEpoch(t *testing.T) {
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	gen := NewSnowflake(5, WithEpoch(epoch))
+	before := time.Now().Truncate(time.Millisecond)
+	id, err := gen.NewIDs(1)
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("TestWithEpoch: got error %q", err)
+	}
+	if ms := id >> 22; ms > after.Sub(epoch).Milliseconds() {
+		t.Errorf("TestWithEpoch: got timestamp %v, expected Milliseconds since %v", ms, epoch)
+	}
+	ts, node, _ := DecomposeSnowflake(id, WithEpoch(epoch))
+	switch {
+	case ts.Before(before) || ts.After(after):
+		t.Errorf("TestWithEpoch: got time %v, expected between %v and %v", ts, before, after)
+	case node != 5:
+		t.Errorf("TestWithEpoch: got node %v, expected 5", node)
+	}
+
+	future := NewSnowflake(5, WithEpoch(time.Now().Add(time.Hour)))
+	if v, err := future.NewIDs(1); err == nil {
+		t.Errorf("TestWithEpoch: expected error before epoch, got %v", v)
+	}
+}
+
+func TestJSSafeSnowflake(t *testing.T) {
+	gen := NewJSSafeSnowflake(31)
+	before := time.Now().Truncate(time.Second)
+	id, err := gen.NewIDs(1 << 15)
+	after := time.Now()
+	if err != nil {
+		t.Fatalf("TestJSSafeSnowflake: got error %q", err)
+	}
+	ts, node, seq := JSSafeLayout.Decompose(id)
+	switch {
+	case id >= 1<<53:
+		t.Errorf("TestJSSafeSnowflake: got %v, expected less than 2^53", id)
+	case ts.Before(before) || ts.After(after):
+		t.Errorf("TestJSSafeSnowflake: got time %v, expected between %v and %v",
+			ts, before, after)
+	case node != 31 || seq != 1<<15-1:
+		t.Errorf("TestJSSafeSnowflake: got node %v sequence %v", node, seq)
+	}
+	if float64(id) != float64(id+1)-1 {
+		t.Errorf("TestJSSafeSnowflake: %v is not exactly representable as float64", id)
+	}
+}
+
+func TestWithWaitOnOverflow(t *testing.T) {
+	gen := NewSnowflake(0, WithWaitOnOverflow())
+	seen := make(map[int64]bool)
+	// Exhausts the sequence at least twice.
+	for i := 0; i < 3; i++ {
+		if _, err := gen.NewIDs(4096); err != nil {
+			t.Fatalf("TestWithWaitOnOverflow %d: got error %q", i, err)
+		}
+		id, err := gen.NewIDs(1)
+		switch {
+		case err != nil:
+			t.Fatalf("TestWithWaitOnOverflow %d: got error %q", i, err)
+		case seen[id]:
+			t.Fatalf("TestWithWaitOnOverflow %d: duplicate %v", i, id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := WithClock(ClockFunc(func() int64 { return now.UnixNano() }))
+	var tests = []struct {
+		layout   Layout
+		nodeMask int64
+		opts     []Option
+		field    string
+	}{
+		{SnowflakeLayout, 1023, nil, ""},
+		{SnowflakeLayout, 1024, nil, "nodeMask"},
+		{SnowflakeLayout, -1, nil, "nodeMask"},
+		{Layout{TimeBits: 41, NodeBits: 11, SeqBits: 12}, 0, nil, "layout"},
+		{Layout{TimeBits: 41, NodeBits: 10}, 0, nil, "layout"},
+		{SnowflakeLayout, 0, []Option{WithEpoch(now.Add(time.Hour))}, "epoch"},
+		{JSSafeLayout, 0, nil, ""},
+		{Layout{TimeBits: 30, SeqBits: 12}, 0, nil, "epoch"},
+		{Layout{TimeBits: 30, SeqBits: 12}, 0, []Option{WithEpoch(now.Add(-time.Hour))}, ""},
+	}
+	for i, test := range tests {
+		gen, err := test.layout.NewSnowflakeChecked(test.nodeMask, append(test.opts, clock)...)
+		var e *ConfigError
+		switch {
+		case test.field == "" && (err != nil || gen == nil):
+			t.Errorf("TestLayoutValidate %d: got error %v", i, err)
+		case test.field != "" && (!errors.As(err, &e) || e.Field != test.field):
+			t.Errorf("TestLayoutValidate %d: got error %v, expected invalid %s", i, err, test.field)
+		}
+	}
+}
+
+func TestLayoutPrecision(t *testing.T) {
+	t.Parallel()
+	year := 365 * 24 * time.Hour
+	var tests = []struct {
+		layout    Layout
+		years     int64
+		perSecond int64
+	}{
+		{SnowflakeLayout, 69, 4096000},
+		{Snowflake10msLayout, 174, 1638400},
+		{SnowflakeSecondLayout, 272, 1 << 20},
+		{JSSafeLayout, 136, 65536},
+		{SnowflakeMicroLayout, 71, 16e6},
+		{Layout{TimeBits: 63, SeqBits: 1, Unit: time.Second}, 292, 2},
+		{Layout{TimeBits: 32, SeqBits: 1, Unit: 2 * time.Second}, 272, 1},
+		{Layout{TimeBits: 30, SeqBits: 1, Unit: 3 * time.Second}, 102, 0},
+		{Layout{TimeBits: 41, SeqBits: 12, Unit: 3 * time.Millisecond}, 209, 1365333},
+	}
+	for i, test := range tests {
+		if years := int64(test.layout.Lifetime() / year); years != test.years {
+			t.Errorf("TestLayoutPrecision %d: got %d years, expected %d", i, years, test.years)
+		}
+		if n := test.layout.PerSecond(); n != test.perSecond {
+			t.Errorf("TestLayoutPrecision %d: got %d per second, expected %d", i, n, test.perSecond)
+		}
+	}
+
+	// Units longer than a Second, or not dividing it.
+	for _, unit := range []time.Duration{2 * time.Second, 3 * time.Millisecond, time.Hour} {
+		l := Layout{TimeBits: 40, NodeBits: 8, SeqBits: 15, Unit: unit}
+		at := time.Date(2024, 1, 1, 0, 0, 7, 5e6, time.UTC)
+		gen := l.NewSnowflake(3, WithClock(ClockFunc(func() int64 { return at.UnixNano() })))
+		v, err := gen.NewIDs(1)
+		if ts, node, seq := l.Decompose(v); err != nil || !ts.Equal(at.Truncate(unit)) || node != 3 || seq != 0 {
+			t.Errorf("TestLayoutPrecision %v: got %v, %v, %v (error %v), expected %v",
+				unit, ts, node, seq, err, at.Truncate(unit))
+		}
+	}
+
+	now := time.Date(2024, 1, 1, 0, 0, 1, 999e6, time.UTC)
+	clock := ClockFunc(func() int64 { return now.UnixNano() })
+	gen := SnowflakeSecondLayout.NewSnowflake(1, WithClock(clock))
+	if v, err := gen.NewIDs(1); err != nil || v != now.Unix()<<30|1<<20 {
+		t.Errorf("TestLayoutPrecision: got %v (error %v), expected %v", v, err, now.Unix()<<30|1<<20)
+	}
+	if v, err := NewTimestampUnit(clock, 10*time.Millisecond).NewIDs(1); err != nil || v != now.UnixMilli()/10 {
+		t.Errorf("TestLayoutPrecision: got %v (error %v), expected %v", v, err, now.UnixMilli()/10)
+	}
+}
+
+func TestMicroSnowflake(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 1500, time.UTC)
+	clock := ClockFunc(func() int64 { return now.UnixNano() })
+	gen := NewMicroSnowflake(255, WithClock(clock))
+	us := now.UnixMicro()
+	var tests = []struct {
+		count, expected int64
+		err             error
+	}{
+		{1, us<<12 | 255<<4, nil},
+		{15, us<<12 | 255<<4 | 15, nil},
+		{1, 0, ErrOverflow},
+	}
+	for i, test := range tests {
+		if v, err := gen.NewIDs(test.count); v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("TestMicroSnowflake %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	now = now.Add(time.Microsecond)
+	v, err := gen.NewIDs(1)
+	if ts, node, seq := SnowflakeMicroLayout.Decompose(v); err != nil || !ts.Equal(now.Truncate(time.Microsecond)) ||
+		node != 255 || seq != 0 {
+		t.Errorf("TestMicroSnowflake: got %v, %v, %v (error %v)", ts, node, seq, err)
+	}
+	if v, err := NewTimestampMicro().NewIDs(1); err != nil || time.Since(time.UnixMicro(v)) > time.Minute {
+		t.Errorf("TestMicroSnowflake: got timestamp %v (error %v)", v, err)
+	}
+}
@@ -0,0 +1,79 @@
+package idgen
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockFreeSnowflake(t *testing.T) {
+	t.Parallel()
+	now := int64(time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	gen := SnowflakeLayout.NewLockFreeSnowflake(3, WithClock(clock))
+	var tests = []struct {
+		count, advance, expected int64
+		err                      error
+	}{
+		{1, 0, 1<<22 | 3<<12, nil},
+		{2, 0, 1<<22 | 3<<12 | 2, nil},
+		{1, int64(time.Millisecond), 2<<22 | 3<<12, nil},
+		{4096, 0, 0, ErrOverflow},
+		{4097, int64(time.Millisecond), 0, ErrUnsupportedCount},
+		{4096, 0, 3<<22 | 3<<12 | 4095, nil},
+		{1, -int64(time.Millisecond), 0, ErrClockMovedBack},
+		{1, int64(2 * time.Millisecond), 4<<22 | 3<<12, nil},
+	}
+	for i, test := range tests {
+		now += test.advance
+		v, err := gen.NewIDs(test.count)
+		if !errors.Is(err, test.err) || v != test.expected {
+			t.Errorf("TestLockFreeSnowflake %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	if _, err := SnowflakeLayout.NewLockFreeSnowflake(1 << 10).NewIDs(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("TestLockFreeSnowflake: got error %v, expected %v", err, ErrOverflow)
+	}
+}
+
+func TestLockFreeSnowflakeConcurrent(t *testing.T) {
+	t.Parallel()
+	gen := SnowflakeLayout.NewLockFreeSnowflake(1, WithWaitOnOverflow())
+	ids := make([][]int64, 8)
+	var wg sync.WaitGroup
+	for g := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 5000; i++ {
+				v, err := gen.NewIDs(1)
+				if err != nil {
+					t.Errorf("TestLockFreeSnowflakeConcurrent: got error %v", err)
+					return
+				}
+				ids[g] = append(ids[g], v)
+			}
+		}()
+	}
+	wg.Wait()
+	seen := map[int64]bool{}
+	for _, g := range ids {
+		for i, v := range g {
+			if seen[v] || (i > 0 && v <= g[i-1]) {
+				t.Fatalf("TestLockFreeSnowflakeConcurrent: duplicate or unordered %v", v)
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func BenchmarkLockFreeSnowflake(b *testing.B) {
+	gen := SnowflakeLayout.NewLockFreeSnowflake(1, WithWaitOnOverflow())
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gen.NewIDs(1)
+		}
+	})
+}
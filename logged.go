@@ -0,0 +1,94 @@
+package idgen
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LogSampling limits how often NewLogged logs.
+type LogSampling struct {
+	// SuccessEvery logs one of every SuccessEvery successful calls, at Debug level. Zero
+	// disables success logs.
+	SuccessEvery int64
+	// ErrorInterval is the minimum time between error logs, at Error level; the number
+	// of errors not logged in between is reported as "suppressed". Zero logs every error.
+	ErrorInterval time.Duration
+}
+
+type logged struct {
+	gen      Interface
+	logger   *slog.Logger
+	name     string
+	sampling LogSampling
+	calls    atomic.Int64
+	mu       sync.Mutex
+	// lastError in Unix Nanoseconds.
+	lastError  int64
+	suppressed int64
+}
+
+// NewLogged wraps gen to log its calls through logger, with the structured fields
+// generator (name), count, first and last (the ID range, see NewIDRange) or error. Safe
+// for concurrent use if gen is.
+func NewLogged(gen Interface, logger *slog.Logger, name string, sampling LogSampling) Interface {
+	return &logged{gen: gen, logger: logger, name: name, sampling: sampling}
+}
+
+func (l *logged) NewIDs(n int64) (int64, error) {
+	if _, ok := l.gen.(RangeInterface); ok && n > 0 {
+		// Batches may not be contiguous: log their actual first ID.
+		r, err := l.NewIDRange(n)
+		return r.Last(), err
+	}
+	id, err := l.gen.NewIDs(n)
+	if err != nil {
+		l.logError(n, err)
+		return id, err
+	}
+	l.logSuccess(NewRange(id-(n-1), n, 1))
+	return id, nil
+}
+
+// NewIDRange implements RangeInterface.
+func (l *logged) NewIDRange(n int64) (Range, error) {
+	r, err := NewIDRange(l.gen, n)
+	if err != nil {
+		l.logError(n, err)
+		return r, err
+	}
+	l.logSuccess(r)
+	return r, nil
+}
+
+func (l *logged) logSuccess(r Range) {
+	if every := l.sampling.SuccessEvery; every > 0 && l.calls.Add(1)%every == 1%every {
+		l.logger.LogAttrs(context.Background(), slog.LevelDebug, "idgen: issued IDs",
+			slog.String("generator", l.name), slog.Int64("count", r.Len()),
+			slog.Int64("first", r.First()), slog.Int64("last", r.Last()))
+	}
+}
+
+func (l *logged) logError(n int64, err error) {
+	var suppressed int64
+	if interval := l.sampling.ErrorInterval; interval > 0 {
+		now := SystemClock.Now()
+		l.mu.Lock()
+		if now-l.lastError < int64(interval) {
+			l.suppressed++
+			l.mu.Unlock()
+			return
+		}
+		l.lastError, suppressed, l.suppressed = now, l.suppressed, 0
+		l.mu.Unlock()
+	}
+	attrs := []slog.Attr{
+		slog.String("generator", l.name), slog.Int64("count", n), slog.Any("error", err),
+	}
+	if suppressed > 0 {
+		attrs = append(attrs, slog.Int64("suppressed", suppressed))
+	}
+	l.logger.LogAttrs(context.Background(), slog.LevelError, "idgen: NewIDs failed", attrs...)
+}
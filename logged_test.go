@@ -0,0 +1,44 @@
+package idgen
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewLogged(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	gen := NewLogged(NewOverflowChecker(4, NewSequential()), logger, "orders",
+		LogSampling{SuccessEvery: 2, ErrorInterval: time.Hour})
+	for _, n := range []int64{2, 1, 3, 1, 20, 20, 20} {
+		gen.NewIDs(n)
+	}
+	expected := []string{
+		`level=DEBUG msg="idgen: issued IDs" generator=orders count=2 first=1 last=2`,
+		`level=DEBUG msg="idgen: issued IDs" generator=orders count=3 first=4 last=6`,
+		`level=ERROR msg="idgen: NewIDs failed" generator=orders count=20 error="*idgen.sequential.NewIDs(20): 27 overflows at bit 4: idgen: overflow"`,
+	}
+	if got := strings.Split(strings.TrimSpace(buf.String()), "\n"); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("TestNewLogged: got\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	buf.Reset()
+	gen = NewLogged(broken{errTest}, logger, "b", LogSampling{})
+	gen.NewIDs(1)
+	gen.NewIDs(1)
+	if n := strings.Count(buf.String(), "level=ERROR"); n != 2 {
+		t.Errorf("TestNewLogged: got %d error logs, expected 2", n)
+	}
+}
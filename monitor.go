@@ -0,0 +1,168 @@
+package idgen
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClockReference returns the time according to a trusted source.
+type ClockReference func(ctx context.Context) (time.Time, error)
+
+// ntpEpochOffset is the number of Seconds from 1900 (NTP's epoch) to 1970.
+const ntpEpochOffset = 2208988800
+
+// NTPReference returns a ClockReference querying the NTP server at addr ("host:port",
+// usually port 123) with a single SNTP request, correcting for the network delay.
+func NTPReference(addr string) ClockReference {
+	return func(ctx context.Context) (time.Time, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "udp", addr)
+		if err != nil {
+			return time.Time{}, err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		} else {
+			conn.SetDeadline(time.Now().Add(5 * time.Second))
+		}
+		req := make([]byte, 48)
+		req[0] = 0x23 // Version 4, client mode.
+		t1 := time.Now()
+		binary.BigEndian.PutUint64(req[40:], ntpTime(t1))
+		if _, err := conn.Write(req); err != nil {
+			return time.Time{}, err
+		}
+		resp := make([]byte, 48)
+		n, err := conn.Read(resp)
+		t4 := time.Now()
+		if err != nil {
+			return time.Time{}, err
+		}
+		if n < 48 || resp[0]&0x7 != 4 || resp[1] == 0 {
+			return time.Time{}, errors.New("idgen: invalid NTP response")
+		}
+		t2 := fromNTPTime(binary.BigEndian.Uint64(resp[32:]))
+		t3 := fromNTPTime(binary.BigEndian.Uint64(resp[40:]))
+		offset := (t2.Sub(t1) + t3.Sub(t4)) / 2
+		return t4.Add(offset), nil
+	}
+}
+
+func ntpTime(t time.Time) uint64 {
+	secs := uint64(t.Unix() + ntpEpochOffset)
+	frac := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return secs<<32 | frac
+}
+
+func fromNTPTime(v uint64) time.Time {
+	secs := int64(v>>32) - ntpEpochOffset
+	nanos := int64((v & 0xffffffff) * uint64(time.Second) >> 32)
+	return time.Unix(secs, nanos)
+}
+
+// ClockMonitor compares the local clock with a ClockReference in the background. When
+// the skew exceeds a threshold it becomes unhealthy, so generators using it (see
+// WithClockMonitor) pause until the clock is corrected. Safe for concurrent use.
+type ClockMonitor struct {
+	clock   Clock
+	ref     ClockReference
+	maxSkew time.Duration
+	onAlarm func(skew time.Duration, err error)
+	skew    atomic.Int64
+	healthy atomic.Bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewClockMonitor starts checking SystemClock, which generators use by default, against
+// ref every interval. onAlarm, if not nil, is called with the skew (local minus
+// reference) when it exceeds maxSkew, or with the error when ref fails; failures do not
+// change the health, so an unreachable reference does not stop generation. Since
+// SystemClock is a MonotonicClock, wall clock corrections do not fix its skew until the
+// process restarts (see MonotonicClock.Drift). Stop it with Close.
+func NewClockMonitor(ref ClockReference, interval, maxSkew time.Duration,
+	onAlarm func(skew time.Duration, err error)) *ClockMonitor {
+	return NewClockMonitorFor(SystemClock, ref, interval, maxSkew, onAlarm)
+}
+
+// NewClockMonitorFor is like NewClockMonitor, checking clock instead: the one given to
+// the generators with WithClock.
+func NewClockMonitorFor(clock Clock, ref ClockReference, interval, maxSkew time.Duration,
+	onAlarm func(skew time.Duration, err error)) *ClockMonitor {
+	m := &ClockMonitor{clock: clock, ref: ref, maxSkew: maxSkew, onAlarm: onAlarm,
+		stop: make(chan struct{})}
+	m.healthy.Store(true)
+	m.wg.Add(1)
+	go m.run(interval)
+	return m
+}
+
+func (m *ClockMonitor) run(interval time.Duration) {
+	defer m.wg.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Check(context.Background())
+		select {
+		case <-ticker.C:
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Check compares the clocks immediately, returning the skew.
+func (m *ClockMonitor) Check(ctx context.Context) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	local, start := m.clock.Now(), time.Now()
+	ref, err := m.ref(ctx)
+	if err != nil {
+		if m.onAlarm != nil {
+			m.onAlarm(0, err)
+		}
+		return 0, err
+	}
+	// Compare with the middle of the round trip.
+	skew := time.Unix(0, local).Add(time.Since(start) / 2).Sub(ref)
+	m.skew.Store(int64(skew))
+	ok := skew <= m.maxSkew && skew >= -m.maxSkew
+	m.healthy.Store(ok)
+	if !ok && m.onAlarm != nil {
+		m.onAlarm(skew, nil)
+	}
+	return skew, nil
+}
+
+// Skew returns the last measured skew, local minus reference time.
+func (m *ClockMonitor) Skew() time.Duration {
+	return time.Duration(m.skew.Load())
+}
+
+// Healthy reports whether the last measured skew was within the threshold.
+func (m *ClockMonitor) Healthy() bool {
+	return m.healthy.Load()
+}
+
+// Close stops the background checks.
+func (m *ClockMonitor) Close() error {
+	m.once.Do(func() { close(m.stop) })
+	m.wg.Wait()
+	return nil
+}
+
+// WithClockMonitor makes the generator return ErrClockSkew while m is unhealthy, instead
+// of generating IDs from a clock that is known to be wrong. m must check the clock of the
+// generator: see NewClockMonitorFor for clocks other than SystemClock.
+func WithClockMonitor(m *ClockMonitor) Option {
+	return func(o *options) {
+		o.monitor = m
+	}
+}
@@ -0,0 +1,108 @@
+package idgen
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClockMonitor(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	offset, refErr := time.Duration(0), error(nil)
+	ref := func(context.Context) (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return time.Now().Add(offset), refErr
+	}
+	var alarms []time.Duration
+	m := NewClockMonitor(ref, time.Hour, time.Second, func(skew time.Duration, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		alarms = append(alarms, skew)
+	})
+	defer m.Close()
+	gen := NewSnowflake(1, WithClockMonitor(m))
+
+	var tests = []struct {
+		offset  time.Duration
+		err     error
+		healthy bool
+		genErr  error
+	}{
+		{0, nil, true, nil},
+		{-time.Minute, nil, false, ErrClockSkew},
+		{-time.Minute, errTest, false, ErrClockSkew},
+		{500 * time.Millisecond, nil, true, nil},
+	}
+	for i, test := range tests {
+		mu.Lock()
+		offset, refErr = test.offset, test.err
+		mu.Unlock()
+		m.Check(context.Background())
+		if _, err := gen.NewIDs(1); m.Healthy() != test.healthy || err != test.genErr {
+			t.Errorf("TestClockMonitor %d: got healthy %v (error %v), expected %v (error %v)",
+				i, m.Healthy(), err, test.healthy, test.genErr)
+		}
+	}
+	mu.Lock()
+	if len(alarms) != 2 || alarms[0] < 59*time.Second || alarms[1] != 0 {
+		t.Errorf("TestClockMonitor: got alarms %v", alarms)
+	}
+	mu.Unlock()
+	if s := m.Skew(); s > -400*time.Millisecond || s < -600*time.Millisecond {
+		t.Errorf("TestClockMonitor: got skew %v, expected about -500ms", s)
+	}
+}
+
+func TestClockMonitorFor(t *testing.T) {
+	t.Parallel()
+	ref := func(context.Context) (time.Time, error) { return time.Now(), nil }
+	if m := NewClockMonitor(ref, time.Hour, time.Second, nil); m.clock != SystemClock {
+		t.Errorf("TestClockMonitorFor: got clock %v, expected SystemClock", m.clock)
+	} else {
+		m.Close()
+	}
+	// The wall clock agrees with the reference, the generator's clock does not.
+	clock := ClockFunc(func() int64 { return time.Now().Add(-time.Minute).UnixNano() })
+	m := NewClockMonitorFor(clock, ref, time.Hour, time.Second, nil)
+	defer m.Close()
+	if skew, err := m.Check(context.Background()); err != nil || m.Healthy() ||
+		skew > -59*time.Second || skew < -61*time.Second {
+		t.Errorf("TestClockMonitorFor: got skew %v (error %v), healthy %v", skew, err, m.Healthy())
+	}
+}
+
+func TestNTPReference(t *testing.T) {
+	t.Parallel()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer conn.Close()
+	const offset = time.Hour
+	go func() {
+		buf := make([]byte, 48)
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil || n != 48 {
+			return
+		}
+		now := ntpTime(time.Now().Add(offset))
+		resp := make([]byte, 48)
+		resp[0], resp[1] = 0x24, 1 // Version 4, server mode, stratum 1.
+		copy(resp[24:], buf[40:48])
+		binary.BigEndian.PutUint64(resp[32:], now)
+		binary.BigEndian.PutUint64(resp[40:], now)
+		conn.WriteTo(resp, addr)
+	}()
+	ref, err := NTPReference(conn.LocalAddr().String())(context.Background())
+	if d := ref.Sub(time.Now().Add(offset)); err != nil || d > time.Second || d < -time.Second {
+		t.Errorf("TestNTPReference: got %v (error %v), expected about an hour ahead", ref, err)
+	}
+	if v := fromNTPTime(ntpTime(time.Unix(1700000000, 5e8))); !v.Equal(time.Unix(1700000000, 5e8)) {
+		t.Errorf("TestNTPReference: got %v after round trip", v)
+	}
+}
@@ -0,0 +1,62 @@
+package idgen
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// NanoIDAlphabet is the default (URL-safe) NanoID alphabet.
+const NanoIDAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+
+// NanoIDSize is the default NanoID length, with collision probability similar to UUIDv4.
+const NanoIDSize = 21
+
+// NewNanoID produces a random NanoID-compatible string of NanoIDSize characters from
+// NanoIDAlphabet, using crypto/rand.
+func NewNanoID() (string, error) {
+	return newNanoID(crand.Reader, NanoIDAlphabet, NanoIDSize)
+}
+
+// NewNanoIDCustom produces a random string of size characters from alphabet (2 to 256
+// distinct bytes), using crypto/rand. Every character is equally likely.
+func NewNanoIDCustom(alphabet string, size int) (string, error) {
+	return newNanoID(crand.Reader, alphabet, size)
+}
+
+func newNanoID(r io.Reader, alphabet string, size int) (string, error) {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return "", fmt.Errorf("NewNanoID(%q): alphabet must have 2 to 256 characters", alphabet)
+	}
+	var seen [256]bool
+	for i := 0; i < len(alphabet); i++ {
+		if seen[alphabet[i]] {
+			return "", fmt.Errorf("NewNanoID(%q): alphabet repeats %q, which would bias IDs",
+				alphabet, alphabet[i])
+		}
+		seen[alphabet[i]] = true
+	}
+	if size <= 0 {
+		return "", fmt.Errorf("NewNanoID(): size must be positive, got %d", size)
+	}
+	// Random bytes are masked to the smallest power of 2 covering the alphabet, and
+	// rejected when out of range, to avoid bias.
+	mask := byte(1<<uint(bits.Len(uint(len(alphabet)-1))) - 1)
+	// Read enough for most cases at once (like the reference implementation).
+	step := 1 + int(1.6*float64(int(mask)+1)*float64(size)/float64(len(alphabet)))
+	id := make([]byte, 0, size)
+	buf := make([]byte, step)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if i := int(b & mask); i < len(alphabet) {
+				if id = append(id, alphabet[i]); len(id) == size {
+					return string(id), nil
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package idgen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestNanoID(t *testing.T) {
+	id, err := NewNanoID()
+	switch {
+	case err != nil:
+		t.Errorf("got error %q", err)
+	case len(id) != NanoIDSize:
+		t.Errorf("got %q, expected length %d", id, NanoIDSize)
+	case strings.Trim(id, NanoIDAlphabet) != "":
+		t.Errorf("got %q, unexpected characters", id)
+	}
+
+	// 5 is masked to 7, so 5, 6 and 7 are rejected.
+	r := bytes.NewReader([]byte{0, 5, 1, 6, 7, 0x0a, 3, 4, 8 + 4, 0, 0, 0, 0, 0, 0, 0})
+	if id, err := newNanoID(r, "abcde", 6); err != nil || id != "abcdee" {
+		t.Errorf("got %q (error %v), expected %q", id, err, "abcdee")
+	}
+
+	for _, test := range []struct {
+		alphabet string
+		size     int
+	}{
+		{"a", 10},
+		{strings.Repeat("a", 257), 10},
+		{"ab", 0},
+		{"abca", 10},
+	} {
+		if id, err := NewNanoIDCustom(test.alphabet, test.size); err == nil {
+			t.Errorf("NewNanoIDCustom(%q, %d): expected error, got %q", test.alphabet, test.size, id)
+		}
+	}
+	if id, err := newNanoID(bytes.NewReader(nil), "ab", 1); err == nil {
+		t.Errorf("exhausted entropy: expected error, got %q", id)
+	}
+}
@@ -0,0 +1,123 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"strconv"
+)
+
+// NodeAllocator claims a nodeMask that no other cooperating process holds, so Snowflake
+// generators can be started without manual node assignment (e.g. in autoscaled fleets).
+// Implementations for different coordination backends are available in subpackages.
+type NodeAllocator interface {
+	// Acquire claims a free nodeMask, held until Release is called or the claim is lost.
+	// ErrNoFreeNode is returned if all of them are taken.
+	Acquire(ctx context.Context) (int64, error)
+	// Release gives the nodeMask back, so other processes can claim it.
+	Release(ctx context.Context) error
+	// Done returns a channel that is closed when the claim is lost (e.g. the backend
+	// could not be reached before the claim expired). IDs generated afterwards may clash.
+	Done() <-chan struct{}
+}
+
+// ErrNoFreeNode is returned by NodeAllocator.Acquire when all nodeMasks are taken.
+var ErrNoFreeNode = errors.New("idgen: no free node")
+
+// NodeFromIP derives a nodeMask from the lowest nodeBits of the machine's first private
+// IPv4 address whose subnet has at most 2^nodeBits addresses (e.g. 10 bits cover a
+// /22), so that machines in the same subnet get distinct values; addresses in larger
+// subnets (e.g. a Docker bridge) are skipped. Prefer it to NodeFromHostname when the
+// network is planned accordingly.
+func NodeFromIP(nodeBits byte) (int64, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return 0, err
+	}
+	return nodeFromAddrs(addrs, nodeBits)
+}
+
+// NodeFromHostname derives a nodeMask by hashing the machine's hostname (FNV-1a) into
+// nodeBits. Hashes can collide: among k machines, the probability of at least one
+// collision is about k²/2^(nodeBits+1) (e.g. 5% for 10 machines and 10 bits, 39% for 32
+// machines), so it is only suitable for small deployments or as a fallback.
+func NodeFromHostname(nodeBits byte) (int64, error) {
+	name, err := os.Hostname()
+	if err != nil {
+		return 0, err
+	}
+	return nodeFromName(name, nodeBits)
+}
+
+// NodeFromEnv reads a nodeMask for NewSnowflake from the environment variable name (e.g.
+// "IDGEN_NODE"), checking that it is in range.
+func NodeFromEnv(name string) (int64, error) {
+	return SnowflakeLayout.NodeFromEnv(name)
+}
+
+// NodeFromEnv reads a nodeMask from the environment variable name, checking that it
+// fits in the layout's NodeBits.
+func (l Layout) NodeFromEnv(name string) (int64, error) {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return 0, fmt.Errorf("NodeFromEnv(%q): not set", name)
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("NodeFromEnv(%q): %v", name, err)
+	}
+	if v < 0 || v >= 1<<l.NodeBits {
+		return 0, fmt.Errorf("NodeFromEnv(%q): %d out of range [0, %d]", name, v, int64(1)<<l.NodeBits-1)
+	}
+	return v, nil
+}
+
+func nodeFromAddrs(addrs []net.Addr, nodeBits byte) (int64, error) {
+	if err := checkNodeBits(nodeBits); err != nil {
+		return 0, err
+	}
+	var rejected *net.IPNet
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip := ipNet.IP.To4(); ip != nil && ip.IsPrivate() {
+			// Larger subnets would give hosts differing only in the masked bits the
+			// same nodeMask.
+			if ones, bits := ipNet.Mask.Size(); bits-ones > int(nodeBits) {
+				if rejected == nil {
+					rejected = ipNet
+				}
+				continue
+			}
+			v := int64(ip[0])<<24 | int64(ip[1])<<16 | int64(ip[2])<<8 | int64(ip[3])
+			return v & (1<<nodeBits - 1), nil
+		}
+	}
+	if rejected != nil {
+		ones, bits := rejected.Mask.Size()
+		return 0, fmt.Errorf("NodeFromIP(%d): subnet %v has %d host bits, more than nodeBits",
+			nodeBits, rejected, bits-ones)
+	}
+	return 0, fmt.Errorf("NodeFromIP(%d): no private IPv4 address found", nodeBits)
+}
+
+func nodeFromName(name string, nodeBits byte) (int64, error) {
+	if err := checkNodeBits(nodeBits); err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64() & (1<<nodeBits - 1)), nil
+}
+
+func checkNodeBits(nodeBits byte) error {
+	if nodeBits == 0 || nodeBits > 62 {
+		return fmt.Errorf("node bits must be between 1 and 62, got %d", nodeBits)
+	}
+	return nil
+}
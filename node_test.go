@@ -0,0 +1,108 @@
+package idgen
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestNodeFromAddrs(t *testing.T) {
+	ipNet := func(s string) net.Addr {
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n.IP = net.ParseIP(s[:len(s)-3])
+		return n
+	}
+	addrs := []net.Addr{
+		ipNet("127.0.0.1/8"),
+		ipNet("8.8.8.8/24"),
+		ipNet("10.1.2.3/16"),
+		ipNet("192.168.0.7/24"),
+	}
+	var tests = []struct {
+		addrs       []net.Addr
+		nodeBits    byte
+		expectError bool
+		expected    int64
+	}{
+		{addrs, 10, false, 7},    // A /16 has more than 2^10 addresses: skipped.
+		{addrs[:3], 10, true, 0}, // Only the /16 is private.
+		{[]net.Addr{ipNet("172.17.0.1/16"), ipNet("192.168.1.5/24")}, 10, false, 1<<8 | 5},
+		{[]net.Addr{ipNet("10.1.2.3/22")}, 10, false, 2<<8 | 3},
+		{addrs, 16, false, 2<<8 | 3},
+		{addrs, 17, false, 1<<16 | 2<<8 | 3},
+		{addrs, 0, true, 0},
+		{addrs[:2], 10, true, 0},
+	}
+	for i, test := range tests {
+		v, err := nodeFromAddrs(test.addrs, test.nodeBits)
+		switch {
+		case test.expectError && err == nil:
+			t.Errorf("TestNodeFromAddrs %d: expected error, got value %v", i, v)
+		case !test.expectError && err != nil:
+			t.Errorf("TestNodeFromAddrs %d: got error %q", i, err)
+		case !test.expectError && v != test.expected:
+			t.Errorf("TestNodeFromAddrs %d: got %v, expected %v", i, v, test.expected)
+		}
+	}
+	if _, err := nodeFromAddrs(addrs[:3], 10); err == nil || !strings.Contains(err.Error(), "10.1.2.3/16") {
+		t.Errorf("TestNodeFromAddrs: got error %v, expected it to name the subnet", err)
+	}
+}
+
+func TestNodeFromName(t *testing.T) {
+	a, err1 := nodeFromName("web-1", 10)
+	b, err2 := nodeFromName("web-1", 10)
+	switch {
+	case err1 != nil || err2 != nil:
+		t.Errorf("TestNodeFromName: got errors %v, %v", err1, err2)
+	case a != b:
+		t.Errorf("TestNodeFromName: not deterministic, got %v and %v", a, b)
+	case a < 0 || a >= 1<<10:
+		t.Errorf("TestNodeFromName: got %v, out of range", a)
+	}
+	if v, err := nodeFromName("web-1", 63); err == nil {
+		t.Errorf("TestNodeFromName: expected error, got value %v", v)
+	}
+	if _, err := NodeFromHostname(10); err != nil {
+		t.Errorf("TestNodeFromName: got error %q", err)
+	}
+}
+
+func TestNodeFromEnv(t *testing.T) {
+	const name = "IDGEN_TEST_NODE"
+	var tests = []struct {
+		value       string
+		layout      Layout
+		expectError bool
+		expected    int64
+	}{
+		{"0", SnowflakeLayout, false, 0},
+		{"1023", SnowflakeLayout, false, 1023},
+		{"1024", SnowflakeLayout, true, 0},
+		{"-1", SnowflakeLayout, true, 0},
+		{"x", SnowflakeLayout, true, 0},
+		{"31", JSSafeLayout, false, 31},
+		{"32", JSSafeLayout, true, 0},
+	}
+	for i, test := range tests {
+		t.Setenv(name, test.value)
+		v, err := test.layout.NodeFromEnv(name)
+		switch {
+		case test.expectError && err == nil:
+			t.Errorf("TestNodeFromEnv %d: expected error, got value %v", i, v)
+		case !test.expectError && err != nil:
+			t.Errorf("TestNodeFromEnv %d: got error %q", i, err)
+		case !test.expectError && v != test.expected:
+			t.Errorf("TestNodeFromEnv %d: got %v, expected %v", i, v, test.expected)
+		}
+	}
+	if v, err := NodeFromEnv(name); err != nil || v != 32 {
+		t.Errorf("TestNodeFromEnv: got %v (error %v), expected 32", v, err)
+	}
+	if v, err := NodeFromEnv("IDGEN_TEST_UNSET"); err == nil {
+		t.Errorf("TestNodeFromEnv: expected error, got value %v", v)
+	}
+}
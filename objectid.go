@@ -0,0 +1,120 @@
+package idgen
+
+import (
+	crand "crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ObjectID is a MongoDB ObjectID: 4 bytes of timestamp (Seconds since Unix epoch), a
+// 5-byte random value unique to the generating process and a 3-byte counter, big-endian.
+type ObjectID [12]byte
+
+// ObjectIDGenerator produces ObjectIDs. Up to 2^24 IDs per second can be generated
+// without clashes. Safe for concurrent use.
+type ObjectIDGenerator struct {
+	random  [5]byte
+	counter uint32
+}
+
+// NewObjectIDGenerator returns an ObjectID generator with random process value and
+// initial counter, as MongoDB drivers do.
+func NewObjectIDGenerator() (*ObjectIDGenerator, error) {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	g := &ObjectIDGenerator{counter: uint32(b[5])<<16 | uint32(b[6])<<8 | uint32(b[7])}
+	copy(g.random[:], b[:5])
+	return g, nil
+}
+
+var defaultObjectIDGenerator = sync.OnceValues(NewObjectIDGenerator)
+
+// NewObjectID returns a new ObjectID from a generator shared by the process.
+func NewObjectID() (ObjectID, error) {
+	g, err := defaultObjectIDGenerator()
+	if err != nil {
+		return ObjectID{}, err
+	}
+	return g.New(), nil
+}
+
+// New returns a new ObjectID.
+func (g *ObjectIDGenerator) New() ObjectID {
+	return g.newObjectID(time.Now().Unix())
+}
+
+func (g *ObjectIDGenerator) newObjectID(sec int64) ObjectID {
+	var oid ObjectID
+	oid[0], oid[1], oid[2], oid[3] = byte(sec>>24), byte(sec>>16), byte(sec>>8), byte(sec)
+	copy(oid[4:9], g.random[:])
+	c := atomic.AddUint32(&g.counter, 1)
+	oid[9], oid[10], oid[11] = byte(c>>16), byte(c>>8), byte(c)
+	return oid
+}
+
+// ParseObjectID decodes the representation produced by ObjectID.String. Uppercase hex
+// digits are accepted.
+func ParseObjectID(s string) (ObjectID, error) {
+	var oid ObjectID
+	if len(s) != 24 {
+		return oid, fmt.Errorf("ParseObjectID(%q): invalid format", s)
+	}
+	if _, err := hex.Decode(oid[:], []byte(s)); err != nil {
+		return ObjectID{}, fmt.Errorf("ParseObjectID(%q): %v", s, err)
+	}
+	return oid, nil
+}
+
+// Time returns the ObjectID timestamp.
+func (oid ObjectID) Time() time.Time {
+	return time.Unix(int64(uint32(oid[0])<<24|uint32(oid[1])<<16|uint32(oid[2])<<8|uint32(oid[3])), 0)
+}
+
+// Counter returns the ObjectID counter.
+func (oid ObjectID) Counter() int64 {
+	return int64(oid[9])<<16 | int64(oid[10])<<8 | int64(oid[11])
+}
+
+// IsZero reports whether oid is the zero value, which MongoDB drivers treat as unset.
+func (oid ObjectID) IsZero() bool {
+	return oid == ObjectID{}
+}
+
+// String returns ObjectID in canonical format (24 lowercase hex digits).
+func (oid ObjectID) String() string {
+	return hex.EncodeToString(oid[:])
+}
+
+// MarshalText implements encoding.TextMarshaler, so ObjectIDs are strings in JSON.
+func (oid ObjectID) MarshalText() ([]byte, error) {
+	return []byte(oid.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (oid *ObjectID) UnmarshalText(b []byte) error {
+	v, err := ParseObjectID(string(b))
+	if err != nil {
+		return err
+	}
+	*oid = v
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (oid ObjectID) MarshalBinary() ([]byte, error) {
+	return oid[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (oid *ObjectID) UnmarshalBinary(b []byte) error {
+	if len(b) != len(oid) {
+		return fmt.Errorf("ObjectID.UnmarshalBinary: got %d bytes, expected %d", len(b), len(oid))
+	}
+	copy(oid[:], b)
+	return nil
+}
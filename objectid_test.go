@@ -0,0 +1,94 @@
+package idgen
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestObjectID(t *testing.T) {
+	ids := []struct {
+		v ObjectID
+		s string
+	}{
+		{ObjectID{}, "000000000000000000000000"},
+		{[...]byte{0x50, 0x7f, 0x1f, 0x77, 0xbc, 0xf8, 0x6c, 0xd7, 0x99, 0x43, 0x90, 0x11},
+			"507f1f77bcf86cd799439011"},
+	}
+	for i, id := range ids {
+		s := id.v.String()
+		v, err := ParseObjectID(id.s)
+		switch {
+		case s != id.s:
+			t.Errorf("%d: repr, got %s, expected %s", i, s, id.s)
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case v != id.v:
+			t.Errorf("%d: parse, got %v, expected %v", i, v, id.v)
+		}
+	}
+	oid := ids[1].v
+	switch {
+	case !oid.Time().Equal(time.Unix(1350508407, 0)):
+		t.Errorf("time, got %v", oid.Time())
+	case oid.Counter() != 0x439011:
+		t.Errorf("counter, got %x", oid.Counter())
+	case oid.IsZero() || !ids[0].v.IsZero():
+		t.Errorf("IsZero, got %v", oid.IsZero())
+	}
+	if v, err := ParseObjectID("507F1F77BCF86CD799439011"); err != nil || v != oid {
+		t.Errorf("uppercase, got %v (error %v)", v, err)
+	}
+	for _, s := range []string{"", "507f1f77bcf86cd79943901", "507f1f77bcf86cd79943901g"} {
+		if v, err := ParseObjectID(s); err == nil {
+			t.Errorf("%q: got %v, expected error", s, v)
+		}
+	}
+}
+
+func TestObjectIDMarshal(t *testing.T) {
+	type doc struct {
+		ID ObjectID `json:"_id"`
+	}
+	oid, _ := ParseObjectID("507f1f77bcf86cd799439011")
+	b, err := json.Marshal(doc{oid})
+	if string(b) != `{"_id":"507f1f77bcf86cd799439011"}` || err != nil {
+		t.Errorf("json, got %s (error %v)", b, err)
+	}
+	var d doc
+	if err := json.Unmarshal(b, &d); err != nil || d.ID != oid {
+		t.Errorf("json, got %v (error %v)", d.ID, err)
+	}
+	if err := json.Unmarshal([]byte(`{"_id":"x"}`), &d); err == nil {
+		t.Errorf("json, expected error")
+	}
+
+	bin, _ := oid.MarshalBinary()
+	var v ObjectID
+	if err := v.UnmarshalBinary(bin); err != nil || v != oid {
+		t.Errorf("binary, got %v (error %v)", v, err)
+	}
+	if err := v.UnmarshalBinary(bin[1:]); err == nil {
+		t.Errorf("binary, expected error")
+	}
+}
+
+func TestObjectIDGenerator(t *testing.T) {
+	g, err := NewObjectIDGenerator()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.counter = 1<<24 - 1
+	a, b := g.newObjectID(1350508407), g.newObjectID(1350508407)
+	switch {
+	case a.Counter() != 0 || b.Counter() != 1:
+		t.Errorf("counter, got %x and %x", a.Counter(), b.Counter())
+	case [5]byte(a[4:9]) != g.random || [5]byte(b[4:9]) != g.random:
+		t.Errorf("random, got %x and %x", a[4:9], b[4:9])
+	case !a.Time().Equal(time.Unix(1350508407, 0)):
+		t.Errorf("time, got %v", a.Time())
+	}
+	if oid, err := NewObjectID(); err != nil || time.Since(oid.Time()) > time.Minute {
+		t.Errorf("NewObjectID, got %v (error %v)", oid, err)
+	}
+}
@@ -0,0 +1,109 @@
+package idgen
+
+import "time"
+
+// Option configures Snowflake-like generators.
+type Option func(*options)
+
+type options struct {
+	// epoch in Unix Nanoseconds.
+	epoch     int64
+	wait      bool
+	waitClock bool
+	clock     Clock
+	store     TimestampStore
+	// storeInterval is how far ahead of the clock the persisted timestamp is kept.
+	storeInterval time.Duration
+	// expvar is the name to publish stats under, if not empty.
+	expvar  string
+	monitor *ClockMonitor
+	// tolerance is the clock regression absorbed without errors.
+	tolerance time.Duration
+	// alarm, if not nil, wraps clock to report suspicious behavior.
+	alarm *ClockAlarm
+	// maxWait bounds waits, if positive.
+	maxWait time.Duration
+}
+
+// WithEpoch makes timestamps count from epoch instead of the Unix epoch, extending the
+// lifetime of the layout (e.g. 41 bits last about 69 years from epoch). Generating IDs
+// before epoch is an error.
+func WithEpoch(epoch time.Time) Option {
+	return func(o *options) {
+		o.epoch = epoch.UnixNano()
+	}
+}
+
+// WithWaitOnOverflow makes NewIDs block until the next timestamp when the sequence is
+// exhausted, instead of returning an overflow error. Requests larger than the sequence
+// capacity are not affected.
+func WithWaitOnOverflow() Option {
+	return func(o *options) {
+		o.wait = true
+	}
+}
+
+// WithWaitOnClockRollback makes NewIDs block until the clock catches up with the last
+// used timestamp when it goes backwards, instead of returning ErrClockMovedBack. Callers
+// may block for as long as the clock was set back.
+func WithWaitOnClockRollback() Option {
+	return func(o *options) {
+		o.waitClock = true
+	}
+}
+
+// WithClockTolerance makes the generator absorb clock regressions of up to d (rounded
+// up to whole timestamp units): IDs keep using the last timestamp until the clock
+// catches up, as if it had stopped. Leap second smearing and NTP slewing move clocks back
+// by fractions of a Millisecond, which would otherwise fail with ErrClockMovedBack.
+// Larger regressions are still handled according to WithWaitOnClockRollback. Since the
+// sequence is not reset while absorbing, it may overflow sooner.
+func WithClockTolerance(d time.Duration) Option {
+	return func(o *options) {
+		o.tolerance = d
+	}
+}
+
+// WithMaxWait bounds how long a call may wait (see WithWaitOnOverflow and
+// WithWaitOnClockRollback): once d has passed, NewIDs fails with ErrWouldBlock, so
+// latency-sensitive callers can degrade gracefully instead of stalling. NewIDsContext
+// also honors the context deadline.
+func WithMaxWait(d time.Duration) Option {
+	return func(o *options) {
+		o.maxWait = d
+	}
+}
+
+// WithClock makes the generator read time from c instead of SystemClock.
+func WithClock(c Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithTimestampStore makes the generator persist timestamps to store, reserving interval
+// ahead of the clock each time it saves. On startup the generator refuses to use
+// timestamps up to the persisted value, returning ErrClockMovedBack (or waiting, with
+// WithWaitOnClockRollback) until the clock passes it. Restarts therefore may stall for up
+// to interval, and saves happen about once per interval while IDs are generated.
+// Non-positive intervals default to one second.
+func WithTimestampStore(store TimestampStore, interval time.Duration) Option {
+	return func(o *options) {
+		if interval <= 0 {
+			interval = time.Second
+		}
+		o.store = store
+		o.storeInterval = interval
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.alarm != nil {
+		o.clock = newAlarmClock(o.clock, *o.alarm)
+	}
+	return o
+}
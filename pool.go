@@ -0,0 +1,50 @@
+package idgen
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// snowflakePool spreads callers over independent snowflakes, each owning a slice of the
+// sequence space, so they do not contend for a single mutex.
+type snowflakePool struct {
+	shards []Interface
+	mask   uint64
+}
+
+// NewSnowflakePool returns a generator with the layout's bit widths that splits the
+// sequence of nodeMask among 2^shardBits snowflakes: the most significant shardBits of
+// the sequence number select the shard. IDs decompose with the layout as usual, but each
+// shard only generates 2^(SeqBits-shardBits) IDs per Unit, and IDs are not ordered across
+// shards within the same Unit. Callers pick shards at random, which scales with
+// GOMAXPROCS as long as there are many more callers than shards are busy.
+// Options apply to every shard, so WithTimestampStore must not be used.
+// Safe for concurrent use.
+func (l Layout) NewSnowflakePool(nodeMask int64, shardBits byte, opts ...Option) (Interface, error) {
+	if shardBits >= l.SeqBits {
+		return nil, fmt.Errorf("NewSnowflakePool(%d, %d): shard bits must be less than %d sequence bits",
+			nodeMask, shardBits, l.SeqBits)
+	}
+	// Shards take the top sequence bits, so they look like extra node bits to each
+	// snowflake while the node stays in place.
+	shard := Layout{
+		TimeBits: l.TimeBits,
+		NodeBits: l.NodeBits + shardBits,
+		SeqBits:  l.SeqBits - shardBits,
+		Unit:     l.Unit,
+	}
+	p := &snowflakePool{shards: make([]Interface, 1<<shardBits), mask: 1<<shardBits - 1}
+	for i := range p.shards {
+		p.shards[i] = shard.NewSnowflake(nodeMask<<shardBits|int64(i), opts...)
+	}
+	return p, nil
+}
+
+func (p *snowflakePool) NewIDs(n int64) (int64, error) {
+	return p.shards[rand.Uint64()&p.mask].NewIDs(n)
+}
+
+// NewIDRange implements RangeInterface.
+func (p *snowflakePool) NewIDRange(n int64) (Range, error) {
+	return NewIDRange(p.shards[rand.Uint64()&p.mask], n)
+}
@@ -0,0 +1,70 @@
+package idgen
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSnowflakePool(t *testing.T) {
+	t.Parallel()
+	if _, err := SnowflakeLayout.NewSnowflakePool(1, 12); err == nil {
+		t.Errorf("TestSnowflakePool: expected error for 12 shard bits")
+	}
+	gen, err := SnowflakeLayout.NewSnowflakePool(5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				v, err := gen.NewIDs(1)
+				if err != nil {
+					// Shards only have 512 IDs per Millisecond.
+					continue
+				}
+				mu.Lock()
+				if seen[v] {
+					t.Errorf("TestSnowflakePool: duplicate %v", v)
+				}
+				seen[v] = true
+				mu.Unlock()
+				if _, node, _ := SnowflakeLayout.Decompose(v); node != 5 {
+					t.Errorf("TestSnowflakePool: got node %v, expected 5", node)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	shards := map[int64]bool{}
+	for v := range seen {
+		_, _, seq := SnowflakeLayout.Decompose(v)
+		shards[seq>>9] = true
+	}
+	if len(shards) != 8 {
+		t.Errorf("TestSnowflakePool: got %d shards used, expected 8", len(shards))
+	}
+}
+
+func BenchmarkSnowflake(b *testing.B) {
+	gen := NewSnowflake(1, WithWaitOnOverflow())
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gen.NewIDs(1)
+		}
+	})
+}
+
+func BenchmarkSnowflakePool(b *testing.B) {
+	gen, _ := SnowflakeLayout.NewSnowflakePool(1, 4, WithWaitOnOverflow())
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			gen.NewIDs(1)
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package idgen
+
+import "sync"
+
+// Prefetcher hands out IDs from a buffered channel kept full by a background goroutine,
+// so the latency of the wrapped generator (locks, clock reads, network round trips) stays
+// off the caller's path. IDs still buffered on Close are never used, leaving gaps.
+// Safe for concurrent use.
+type Prefetcher struct {
+	ids  chan int64
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+	// err is written before ids is closed, so receivers that see it closed may read it.
+	err error
+}
+
+// NewPrefetcher starts prefetching up to size IDs from gen, one at a time. When gen
+// fails, buffered IDs are still handed out and then Next returns the error.
+func NewPrefetcher(gen Interface, size int) *Prefetcher {
+	p := &Prefetcher{ids: make(chan int64, size), done: make(chan struct{})}
+	p.wg.Add(1)
+	go p.fill(gen)
+	return p
+}
+
+func (p *Prefetcher) fill(gen Interface) {
+	defer p.wg.Done()
+	defer close(p.ids)
+	for {
+		id, err := gen.NewIDs(1)
+		if err != nil {
+			p.err = err
+			return
+		}
+		select {
+		case p.ids <- id:
+		case <-p.done:
+			p.err = ErrClosed
+			return
+		}
+	}
+}
+
+// Next returns a prefetched ID, waiting for one if the buffer is empty.
+func (p *Prefetcher) Next() (int64, error) {
+	select {
+	case <-p.done:
+		return 0, ErrClosed
+	default:
+	}
+	id, ok := <-p.ids
+	if !ok {
+		return 0, p.err
+	}
+	return id, nil
+}
+
+// NewIDs implements Interface. It only accepts n=1.
+func (p *Prefetcher) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(p, n)
+	}
+	return p.Next()
+}
+
+// Close stops prefetching and waits for the background goroutine, which may be blocked
+// in the wrapped generator. Next returns ErrClosed afterwards.
+func (p *Prefetcher) Close() error {
+	p.once.Do(func() { close(p.done) })
+	p.wg.Wait()
+	return nil
+}
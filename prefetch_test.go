@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrefetcher(t *testing.T) {
+	t.Parallel()
+	p := NewPrefetcher(NewOverflowChecker(3, NewSequential()), 4)
+	for i := int64(1); i < 8; i++ {
+		if v, err := p.Next(); err != nil || v != i {
+			t.Errorf("TestPrefetcher %d: got %v (error %v)", i, v, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := p.Next(); !errors.Is(err, ErrOverflow) {
+			t.Errorf("TestPrefetcher: got error %v, expected %v", err, ErrOverflow)
+		}
+	}
+	p.Close()
+
+	p = NewPrefetcher(NewSequential(), 4)
+	if _, err := p.NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestPrefetcher: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+	if v, err := p.NewIDs(1); err != nil || v != 1 {
+		t.Errorf("TestPrefetcher: got %v (error %v), expected 1", v, err)
+	}
+	p.Close()
+	p.Close()
+	if _, err := p.Next(); err != ErrClosed {
+		t.Errorf("TestPrefetcher: got error %v, expected %v", err, ErrClosed)
+	}
+}
+
+func BenchmarkPrefetcher(b *testing.B) {
+	p := NewPrefetcher(NewSequential(), 1024)
+	defer p.Close()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := p.Next(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package idgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Prefixed wraps an ID generator, formatting IDs as a type prefix followed by the base62
+// representation of the ID, like Stripe's "cus_4QE41GDczMg5d5". Different entity types
+// can share a generator and still be told apart. Safe for concurrent use if gen is.
+type Prefixed struct {
+	gen    Interface
+	prefix string
+}
+
+// NewPrefixed returns a Prefixed generator around gen. The prefix usually ends with a
+// separator, e.g. "ord_".
+func NewPrefixed(prefix string, gen Interface) *Prefixed {
+	return &Prefixed{gen: gen, prefix: prefix}
+}
+
+// NewID generates an ID and returns its prefixed form.
+func (p *Prefixed) NewID() (string, error) {
+	id, err := p.gen.NewIDs(1)
+	if err != nil {
+		return "", err
+	}
+	return p.Format(id), nil
+}
+
+// Format returns the prefixed form of id. Negative IDs are encoded as their 64-bit two's
+// complement.
+func (p *Prefixed) Format(id int64) string {
+	return p.prefix + encodeBase(uint64(id), base62)
+}
+
+// Parse validates the prefix of s and returns the ID.
+func (p *Prefixed) Parse(s string) (int64, error) {
+	if !strings.HasPrefix(s, p.prefix) {
+		return 0, fmt.Errorf("Prefixed.Parse(%q): expected prefix %q", s, p.prefix)
+	}
+	u, err := decodeBase(s[len(p.prefix):], base62)
+	if err != nil {
+		return 0, fmt.Errorf("Prefixed.Parse(%q): %v", s, err)
+	}
+	return int64(u), nil
+}
+
+// encodeBase returns u in the positional numeral system of alphabet, without leading
+// zeros.
+func encodeBase(u uint64, alphabet string) string {
+	var s [64]byte
+	base := uint64(len(alphabet))
+	i := len(s)
+	for {
+		i--
+		s[i] = alphabet[u%base]
+		if u /= base; u == 0 {
+			break
+		}
+	}
+	return string(s[i:])
+}
+
+// decodeBase is the inverse of encodeBase, checking for invalid digits and overflow.
+func decodeBase(s, alphabet string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty")
+	}
+	base := uint64(len(alphabet))
+	var u uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(alphabet, s[i])
+		if d < 0 {
+			return 0, fmt.Errorf("invalid character %q", s[i])
+		}
+		if u > (1<<64-1-uint64(d))/base {
+			return 0, fmt.Errorf("overflow")
+		}
+		u = u*base + uint64(d)
+	}
+	return u, nil
+}
@@ -0,0 +1,40 @@
+package idgen
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPrefixed(t *testing.T) {
+	p := NewPrefixed("cus_", NewSequential())
+	tests := []struct {
+		v int64
+		s string
+	}{
+		{0, "cus_0"},
+		{61, "cus_z"},
+		{62, "cus_10"},
+		{math.MaxInt64, "cus_AzL8n0Y58m7"},
+		{-1, "cus_LygHa16AHYF"},
+	}
+	for i, test := range tests {
+		if s := p.Format(test.v); s != test.s {
+			t.Errorf("%d: format, got %s, expected %s", i, s, test.s)
+		}
+		if v, err := p.Parse(test.s); err != nil || v != test.v {
+			t.Errorf("%d: parse, got %d (error %v), expected %d", i, v, err, test.v)
+		}
+	}
+	for _, s := range []string{"", "cus_", "ord_1", "cus1", "cus_1-", "cus_LygHa16AHYG"} {
+		if v, err := p.Parse(s); err == nil {
+			t.Errorf("Parse(%q): expected error, got %d", s, v)
+		}
+	}
+
+	if s, err := p.NewID(); err != nil || s != "cus_1" {
+		t.Errorf("NewID: got %q (error %v), expected %q", s, err, "cus_1")
+	}
+	if _, err := NewPrefixed("x_", broken{errTest}).NewID(); err != errTest {
+		t.Errorf("NewID: got error %v, expected %v", err, errTest)
+	}
+}
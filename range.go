@@ -0,0 +1,202 @@
+package idgen
+
+import (
+	"context"
+	"iter"
+	"sync/atomic"
+)
+
+// Range describes the IDs allocated by one NewIDs call: Len IDs starting at First, each
+// one Step after the previous.
+type Range struct {
+	first, step, n int64
+}
+
+// RangeInterface is implemented by generators whose batches are not contiguous (e.g.
+// Sonyflake, where the sequence is not in the least significant bits) or that want to
+// describe them directly.
+type RangeInterface interface {
+	NewIDRange(n int64) (Range, error)
+}
+
+// NewRange returns the Range of n IDs starting at first, step apart. step must not be 0.
+func NewRange(first, n, step int64) Range {
+	if n < 0 {
+		n = 0
+	}
+	return Range{first: first, step: step, n: n}
+}
+
+// NewIDRange generates n IDs with gen and returns them as a Range, avoiding the "last ID,
+// subtract n-1" arithmetic. Generators not implementing RangeInterface are assumed to
+// allocate contiguous batches, which holds for all of them in this package except
+// Snowflake-like ones (which implement it).
+func NewIDRange(gen Interface, n int64) (Range, error) {
+	if n < 1 {
+		return Range{}, unsupportedCount(gen, n)
+	}
+	if r, ok := gen.(RangeInterface); ok {
+		return r.NewIDRange(n)
+	}
+	last, err := gen.NewIDs(n)
+	if err != nil {
+		return Range{}, err
+	}
+	return NewRange(last-(n-1), n, 1), nil
+}
+
+// First returns the first ID, or 0 if the range is empty.
+func (r Range) First() int64 {
+	if r.n == 0 {
+		return 0
+	}
+	return r.first
+}
+
+// Last returns the last ID, or 0 if the range is empty.
+func (r Range) Last() int64 {
+	if r.n == 0 {
+		return 0
+	}
+	return r.first + (r.n-1)*r.step
+}
+
+// Len returns how many IDs are in the range.
+func (r Range) Len() int64 {
+	return r.n
+}
+
+// Step returns the difference between consecutive IDs.
+func (r Range) Step() int64 {
+	return r.step
+}
+
+// Contains reports whether id is in the range.
+func (r Range) Contains(id int64) bool {
+	if r.n == 0 {
+		return false
+	}
+	lo, hi := r.first, r.Last()
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	return id >= lo && id <= hi && (id-r.first)%r.step == 0
+}
+
+// All iterates over the IDs in order.
+func (r Range) All() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for i, id := int64(0), r.first; i < r.n; i, id = i+1, id+r.step {
+			if !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+func (s *snowflake) NewIDRange(n int64) (Range, error) {
+	last, err := s.NewIDs(n)
+	if err != nil {
+		return Range{}, err
+	}
+	step := int64(1) << s.seqBits
+	return NewRange(last-(n-1)*step, n, step), nil
+}
+
+// Span describes IDs allocated in several Ranges, e.g. a request too large for one
+// Snowflake timestamp. Ranges are in generation order and do not overlap.
+type Span []Range
+
+// SpanInterface is implemented by generators able to satisfy requests larger than a
+// single batch by allocating several.
+type SpanInterface interface {
+	NewIDSpan(n int64) (Span, error)
+}
+
+// NewIDSpan generates n IDs with gen and returns them as a Span. Snowflake-like
+// generators allocate as many timestamps as needed, waiting for the clock (n/4096
+// Milliseconds with SnowflakeLayout), so bulk jobs can reserve any number of IDs in one
+// call; other generators return a single Range, see NewIDRange.
+func NewIDSpan(gen Interface, n int64) (Span, error) {
+	if s, ok := gen.(SpanInterface); ok {
+		return s.NewIDSpan(n)
+	}
+	r, err := NewIDRange(gen, n)
+	if err != nil {
+		return nil, err
+	}
+	return Span{r}, nil
+}
+
+// Len returns how many IDs are in the span.
+func (s Span) Len() int64 {
+	var n int64
+	for _, r := range s {
+		n += r.Len()
+	}
+	return n
+}
+
+// First returns the first ID, or 0 if the span is empty.
+func (s Span) First() int64 {
+	if len(s) == 0 {
+		return 0
+	}
+	return s[0].First()
+}
+
+// Last returns the last ID, or 0 if the span is empty.
+func (s Span) Last() int64 {
+	if len(s) == 0 {
+		return 0
+	}
+	return s[len(s)-1].Last()
+}
+
+// Contains reports whether id is in the span.
+func (s Span) Contains(id int64) bool {
+	for _, r := range s {
+		if r.Contains(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// All iterates over the IDs in order.
+func (s Span) All() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for _, r := range s {
+			for id := range r.All() {
+				if !yield(id) {
+					return
+				}
+			}
+		}
+	}
+}
+
+func (s *snowflake) NewIDSpan(n int64) (Span, error) {
+	if n < 1 {
+		return nil, unsupportedCount(s, n)
+	}
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	step := int64(1) << s.seqBits
+	var span Span
+	for remaining := n; remaining > 0; {
+		chunk := min(remaining, s.seqSize)
+		// Use up the current timestamp first, if it is still current.
+		if free := s.seqSize - 1 - atomic.LoadInt64(&s.sequential.value); s.lastTimestamp != -1 &&
+			free > 0 && free < chunk {
+			chunk = free
+		}
+		last, err := s.newIDs(context.Background(), chunk, true)
+		if err != nil {
+			return nil, err
+		}
+		span = append(span, NewRange(last-(chunk-1)*step, chunk, step))
+		remaining -= chunk
+	}
+	return span, nil
+}
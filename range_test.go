@@ -0,0 +1,177 @@
+package idgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		r                 Range
+		first, last, size int64
+		in, out           []int64
+	}{
+		{NewRange(5, 3, 1), 5, 7, 3, []int64{5, 6, 7}, []int64{4, 8}},
+		{NewRange(1<<16, 3, 1<<16), 1 << 16, 3 << 16, 3, []int64{2 << 16}, []int64{1<<16 + 1, 0}},
+		{NewRange(-1, 3, -1), -1, -3, 3, []int64{-2}, []int64{0, -4}},
+		{NewRange(9, 0, 1), 0, 0, 0, nil, []int64{0, 9}},
+	}
+	for i, test := range tests {
+		r := test.r
+		if r.First() != test.first || r.Last() != test.last || r.Len() != test.size {
+			t.Errorf("TestRange %d: got [%v, %v] len %v, expected [%v, %v] len %v",
+				i, r.First(), r.Last(), r.Len(), test.first, test.last, test.size)
+		}
+		for _, id := range test.in {
+			if !r.Contains(id) {
+				t.Errorf("TestRange %d: expected %v in range", i, id)
+			}
+		}
+		for _, id := range test.out {
+			if r.Contains(id) {
+				t.Errorf("TestRange %d: expected %v out of range", i, id)
+			}
+		}
+		ids := slices.Collect(r.All())
+		if int64(len(ids)) != r.Len() || (len(ids) > 0 && ids[len(ids)-1] != r.Last()) {
+			t.Errorf("TestRange %d: got IDs %v", i, ids)
+		}
+	}
+}
+
+func TestNewIDRange(t *testing.T) {
+	t.Parallel()
+	now := int64(time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	seg, err := NewSegment(&segmentStore{}, 10, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		gen         Interface
+		first, step int64
+	}{
+		{NewSequential(), 1, 1},
+		{NewNegSequential(), -1<<63 + 1, 1},
+		{NewSnowflake(3, WithClock(clock)), 1<<22 | 3<<12, 1},
+		{seg, 1, 1},
+	}
+	for i, test := range tests {
+		r, err := NewIDRange(test.gen, 3)
+		if err != nil || r.First() != test.first || r.Step() != test.step || r.Len() != 3 {
+			t.Errorf("TestNewIDRange %d: got %+v (error %v), expected first %v step %v",
+				i, r, err, test.first, test.step)
+		}
+		if _, err := NewIDRange(test.gen, 0); !errors.Is(err, ErrUnsupportedCount) {
+			t.Errorf("TestNewIDRange %d: got error %v, expected %v", i, err, ErrUnsupportedCount)
+		}
+	}
+
+	r, err := NewIDRange(NewSonyflake(1), 3)
+	if err != nil || r.Step() != 1<<16 || r.Last()-r.First() != 2<<16 || r.Last()&0xffff != 1 {
+		t.Errorf("TestNewIDRange: got %+v (error %v) for Sonyflake", r, err)
+	}
+}
+
+func TestNewIDRangeWrapped(t *testing.T) {
+	t.Parallel()
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	limited, err := NewRateLimited(NewSonyflake(1), 1e6, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bloom, err := NewBloomChecked(NewSonyflake(1), BloomConfig{Capacity: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		gen  Interface
+		step int64
+	}{
+		{NewLogged(NewSonyflake(1), logger, "orders", LogSampling{SuccessEvery: 1}), 1 << 16},
+		{limited, 1 << 16},
+		{bloom, 1 << 16},
+	}
+	for i, test := range tests {
+		r, err := NewIDRange(test.gen, 3)
+		if err != nil || r.Step() != test.step || r.Last()-r.First() != 2*test.step {
+			t.Errorf("TestNewIDRangeWrapped %d: got %+v (error %v), expected step %v",
+				i, r, err, test.step)
+		}
+		if i == 0 {
+			// The log has the real first ID, not last-(n-1).
+			want := fmt.Sprintf("count=3 first=%d last=%d", r.First(), r.Last())
+			if !strings.Contains(logs.String(), want) {
+				t.Errorf("TestNewIDRangeWrapped: got logs %q, expected %q", logs.String(), want)
+			}
+		}
+	}
+}
+
+func TestNewIDSpan(t *testing.T) {
+	t.Parallel()
+	// Every reading moves the clock by 0.1ms, so waiting for the next timestamp is quick.
+	now := int64(time.Millisecond)
+	gen := NewSnowflake(1, WithClock(ClockFunc(func() int64 {
+		now += 100 * int64(time.Microsecond)
+		return now
+	})))
+	if _, err := gen.NewIDs(4000); err != nil {
+		t.Fatal(err)
+	}
+	span, err := NewIDSpan(gen, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := slices.Collect(span.All())
+	if span.Len() != 10000 || len(ids) != 10000 || span.First() != ids[0] || span.Last() != ids[len(ids)-1] {
+		t.Fatalf("TestNewIDSpan: got %d IDs in %v", len(ids), span)
+	}
+	if span[0].Len() != 96 {
+		t.Errorf("TestNewIDSpan: got %d IDs in the first range, expected the 96 left", span[0].Len())
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("TestNewIDSpan: got %v after %v", ids[i], ids[i-1])
+		}
+	}
+	if !span.Contains(ids[5000]) || span.Contains(ids[5000]+1<<12) || span.Contains(ids[0]-1) {
+		t.Errorf("TestNewIDSpan: wrong Contains results")
+	}
+	if v, err := gen.NewIDs(1); err != nil || v <= span.Last() {
+		t.Errorf("TestNewIDSpan: got %v (error %v) after %v", v, err, span.Last())
+	}
+
+	span, err = NewIDSpan(NewSequential(), 3)
+	if err != nil || len(span) != 1 || span.First() != 1 || span.Last() != 3 {
+		t.Errorf("TestNewIDSpan: got %v (error %v)", span, err)
+	}
+	if _, err := NewIDSpan(gen, 0); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestNewIDSpan: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+	var empty Span
+	if empty.Len() != 0 || empty.First() != 0 || empty.Last() != 0 || empty.Contains(0) {
+		t.Errorf("TestNewIDSpan: got non-empty %v", empty)
+	}
+}
+
+func TestSnowflakeLargeRequest(t *testing.T) {
+	t.Parallel()
+	now := int64(time.Millisecond)
+	gen := NewSnowflake(1, WithClock(ClockFunc(func() int64 { return now })))
+	// A fresh timestamp used to hide requests larger than the sequence.
+	if v, err := gen.NewIDs(5000); !errors.Is(err, ErrOverflow) {
+		t.Errorf("TestSnowflakeLargeRequest: got %v (error %v), expected %v", v, err, ErrOverflow)
+	}
+	if v, err := gen.NewIDs(4096); err != nil || v != 1<<22|1<<12|4095 {
+		t.Errorf("TestSnowflakeLargeRequest: got %v (error %v), expected %v", v, err, 1<<22|1<<12|4095)
+	}
+}
@@ -0,0 +1,123 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RateLimited wraps a generator with a token bucket, limiting how many IDs it issues per
+// second (e.g. to protect a database-backed allocator from bursty callers). Each ID
+// takes one token. Safe for concurrent use.
+type RateLimited struct {
+	gen   Interface
+	clock Clock
+	mu    sync.Mutex
+	// rate is tokens per Nanosecond.
+	rate   float64
+	burst  float64
+	tokens float64
+	// last refill in Unix Nanoseconds.
+	last int64
+}
+
+// NewRateLimited returns gen limited to perSecond IDs per second on average, allowing
+// bursts of up to burst IDs (also the largest accepted n). The bucket starts full.
+func NewRateLimited(gen Interface, perSecond float64, burst int64) (*RateLimited, error) {
+	if perSecond <= 0 || burst < 1 {
+		return nil, fmt.Errorf("NewRateLimited(%v, %d): rate and burst must be positive",
+			perSecond, burst)
+	}
+	return &RateLimited{
+		gen:    gen,
+		clock:  SystemClock,
+		rate:   perSecond / float64(time.Second),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   SystemClock.Now(),
+	}, nil
+}
+
+// Errors of RateLimited, matching both ErrRateLimited and the reason not to wait.
+var (
+	errRateLimitedWouldBlock = fmt.Errorf("%w: %w", ErrRateLimited, ErrWouldBlock)
+	errRateLimitedDeadline   = fmt.Errorf("%w: %w", ErrRateLimited, ErrDeadline)
+)
+
+// NewIDs implements Interface, failing fast with ErrRateLimited (and ErrWouldBlock) when
+// there are not enough tokens.
+func (r *RateLimited) NewIDs(n int64) (int64, error) {
+	if _, err := r.take(n, 0, errRateLimitedWouldBlock); err != nil {
+		return 0, err
+	}
+	return r.gen.NewIDs(n)
+}
+
+// NewIDRange implements RangeInterface, like NewIDs.
+func (r *RateLimited) NewIDRange(n int64) (Range, error) {
+	if _, err := r.take(n, 0, errRateLimitedWouldBlock); err != nil {
+		return Range{}, err
+	}
+	return NewIDRange(r.gen, n)
+}
+
+// NewIDsContext is like NewIDs, waiting for tokens until ctx is done, and passes ctx to
+// the wrapped generator. Waiting callers are served in arrival order. When the tokens
+// would only be available after the deadline of ctx, it fails at once with
+// ErrRateLimited (and ErrDeadline).
+func (r *RateLimited) NewIDsContext(ctx context.Context, n int64) (int64, error) {
+	deadline := int64(1<<63 - 1)
+	if d, ok := ctx.Deadline(); ok {
+		deadline = d.UnixNano()
+	}
+	wait, err := r.take(n, deadline, errRateLimitedDeadline)
+	if err != nil {
+		return 0, err
+	}
+	if wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			r.refund(n)
+			return 0, contextError(ctx.Err())
+		}
+	}
+	return NewIDsContext(ctx, r.gen, n)
+}
+
+// take removes n tokens, failing with late unless they are available by deadline (Unix
+// Nanoseconds). Tokens may go negative, reserving future ones: the caller must wait for
+// the returned duration before using them.
+func (r *RateLimited) take(n int64, deadline int64, late error) (time.Duration, error) {
+	if n < 1 || float64(n) > r.burst {
+		return 0, unsupportedCount(r, n)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.clock.Now()
+	r.refill(now)
+	var wait time.Duration
+	if missing := float64(n) - r.tokens; missing > 0 {
+		if wait = time.Duration(missing / r.rate); now+int64(wait) > deadline {
+			return 0, late
+		}
+	}
+	r.tokens -= float64(n)
+	return wait, nil
+}
+
+func (r *RateLimited) refund(n int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens += float64(n)
+}
+
+func (r *RateLimited) refill(now int64) {
+	if now > r.last {
+		r.tokens = min(r.burst, r.tokens+float64(now-r.last)*r.rate)
+		r.last = now
+	}
+}
@@ -0,0 +1,67 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimited(t *testing.T) {
+	t.Parallel()
+	now := int64(0)
+	r, err := NewRateLimited(NewSequential(), 10, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.clock, r.last = ClockFunc(func() int64 { return now }), 0
+	var tests = []struct {
+		count, advance, expected int64
+		err                      error
+	}{
+		{3, 0, 3, nil},
+		{2, 0, 5, nil},
+		{1, 0, 0, ErrRateLimited},
+		{1, int64(100 * time.Millisecond), 6, nil},
+		{2, int64(100 * time.Millisecond), 0, ErrRateLimited},
+		{6, int64(time.Hour), 0, ErrUnsupportedCount},
+		{5, 0, 11, nil},
+	}
+	for i, test := range tests {
+		now += test.advance
+		v, err := r.NewIDs(test.count)
+		if !errors.Is(err, test.err) || v != test.expected {
+			t.Errorf("TestRateLimited %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	if _, err := NewRateLimited(NewSequential(), 0, 1); err == nil {
+		t.Errorf("TestRateLimited: expected error for zero rate")
+	}
+}
+
+func TestRateLimitedContext(t *testing.T) {
+	t.Parallel()
+	r, _ := NewRateLimited(NewSequential(), 100, 1)
+	r.NewIDs(1)
+	start := time.Now()
+	if v, err := r.NewIDsContext(context.Background(), 1); err != nil || v != 2 {
+		t.Errorf("TestRateLimitedContext: got %v (error %v), expected 2", v, err)
+	}
+	if d := time.Since(start); d < 5*time.Millisecond {
+		t.Errorf("TestRateLimitedContext: waited %v, expected about 10ms", d)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := r.NewIDsContext(ctx, 1); !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrDeadline) {
+		t.Errorf("TestRateLimitedContext: got error %v, expected %v and %v", err, ErrRateLimited, ErrDeadline)
+	}
+	if _, err := r.NewIDs(1); !errors.Is(err, ErrRateLimited) || !errors.Is(err, ErrWouldBlock) {
+		t.Errorf("TestRateLimitedContext: got error %v, expected %v and %v", err, ErrRateLimited, ErrWouldBlock)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.NewIDsContext(ctx, 1); err != context.Canceled {
+		t.Errorf("TestRateLimitedContext: got error %v, expected %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,150 @@
+// Package redisnode allocates Snowflake nodeMasks using Redis keys with expiration.
+package redisnode
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	// renew extends the key expiration only if it is still owned by the caller.
+	renew = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+	// release deletes the key only if it is still owned by the caller.
+	release = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// Allocator claims a nodeMask by setting the key prefix+nodeMask if it does not exist
+// (SET NX) with a TTL, which is renewed in the background every third of the TTL. If
+// the process dies, the key expires and the nodeMask becomes free again. If renewal
+// fails for longer than the TTL, Done is closed. Safe for concurrent use.
+type Allocator struct {
+	client   redis.UniversalClient
+	prefix   string
+	nodeBits byte
+	ttl      time.Duration
+
+	mu     sync.Mutex
+	key    string
+	token  string
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var (
+	_ idgen.NodeAllocator = (*Allocator)(nil)
+	_ io.Closer           = (*Allocator)(nil)
+)
+
+// MinTTL is the shortest ttl accepted: renewals run every ttl/3 and Redis expiries are
+// set in Milliseconds.
+const MinTTL = 3 * time.Millisecond
+
+// New returns an Allocator for nodeMasks of nodeBits (e.g.
+// idgen.SnowflakeLayout.NodeBits), using keys under prefix (e.g. "idgen:myservice:").
+// Claims expire after ttl unless renewed; Acquire fails if it is below MinTTL.
+func New(client redis.UniversalClient, prefix string, nodeBits byte, ttl time.Duration) *Allocator {
+	return &Allocator{client: client, prefix: prefix, nodeBits: nodeBits, ttl: ttl}
+}
+
+// Acquire implements idgen.NodeAllocator, claiming the lowest free nodeMask.
+func (a *Allocator) Acquire(ctx context.Context) (int64, error) {
+	if a.ttl < MinTTL {
+		return 0, fmt.Errorf("redisnode: ttl must be at least %v, got %v", MinTTL, a.ttl)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel != nil {
+		return 0, errors.New("redisnode: already acquired")
+	}
+	// The token identifies this claim, so an expired claim cannot be renewed or
+	// released after another process takes the nodeMask.
+	var random [8]byte
+	if _, err := crand.Read(random[:]); err != nil {
+		return 0, err
+	}
+	host, _ := os.Hostname()
+	token := fmt.Sprintf("%s/%d/%s", host, os.Getpid(), hex.EncodeToString(random[:]))
+
+	for node := int64(0); node < 1<<a.nodeBits; node++ {
+		key := a.prefix + strconv.FormatInt(node, 10)
+		ok, err := a.client.SetNX(ctx, key, token, a.ttl).Result()
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		renewCtx, cancel := context.WithCancel(context.Background())
+		a.key, a.token, a.cancel, a.done = key, token, cancel, make(chan struct{})
+		go a.renew(renewCtx, key, token, a.done)
+		return node, nil
+	}
+	return 0, idgen.ErrNoFreeNode
+}
+
+func (a *Allocator) renew(ctx context.Context, key, token string, done chan struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(a.ttl / 3)
+	defer ticker.Stop()
+	deadline := time.Now().Add(a.ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := renew.Run(ctx, a.client, []string{key}, token, a.ttl.Milliseconds()).Int()
+		switch {
+		case err == nil && n == 1:
+			deadline = time.Now().Add(a.ttl)
+		case err == nil:
+			// The key expired and may have been taken by another process.
+			return
+		case time.Now().After(deadline):
+			return
+		}
+	}
+}
+
+// Release implements idgen.NodeAllocator, deleting the key if it is still owned.
+func (a *Allocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.cancel == nil {
+		return nil
+	}
+	a.cancel()
+	a.cancel = nil
+	return release.Run(ctx, a.client, []string{a.key}, a.token).Err()
+}
+
+// Done implements idgen.NodeAllocator. It returns nil before Acquire.
+func (a *Allocator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done
+}
+
+// Close implements io.Closer, releasing the nodeMask (see Release).
+func (a *Allocator) Close() error {
+	return a.Release(context.Background())
+}
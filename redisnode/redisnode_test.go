@@ -0,0 +1,61 @@
+package redisnode
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestAllocatorTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Second, 2 * time.Millisecond} {
+		if node, err := New(nil, "idgen-test:", 1, ttl).Acquire(context.Background()); err == nil {
+			t.Errorf("ttl %v: got %v, expected error", ttl, node)
+		}
+	}
+}
+
+// TestAllocator requires a Redis server, e.g. REDIS_ADDR=localhost:6379.
+func TestAllocator(t *testing.T) {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_ADDR not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	ctx := context.Background()
+	prefix := "idgen-test:" + strconv.FormatInt(time.Now().UnixNano(), 10) + ":"
+
+	a, b, c := New(client, prefix, 1, time.Second), New(client, prefix, 1, time.Second),
+		New(client, prefix, 1, time.Second)
+	nodeA, errA := a.Acquire(ctx)
+	nodeB, errB := b.Acquire(ctx)
+	switch {
+	case errA != nil || errB != nil:
+		t.Fatalf("got errors %v, %v", errA, errB)
+	case nodeA == nodeB:
+		t.Fatalf("got same node %v", nodeA)
+	}
+	if node, err := c.Acquire(ctx); err != idgen.ErrNoFreeNode {
+		t.Errorf("got %v (error %v), expected error %v", node, err, idgen.ErrNoFreeNode)
+	}
+	// Longer than the TTL: renewal must hold the claim.
+	time.Sleep(3 * time.Second)
+	select {
+	case <-a.Done():
+		t.Errorf("claim lost")
+	default:
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Errorf("got error %q", err)
+	}
+	if node, err := c.Acquire(ctx); err != nil || node != nodeA {
+		t.Errorf("got %v (error %v), expected %v", node, err, nodeA)
+	}
+	b.Release(ctx)
+	c.Release(ctx)
+}
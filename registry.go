@@ -0,0 +1,334 @@
+package idgen
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Factory builds a generator from the parameters of a spec (see New).
+type Factory func(p *Params) (Interface, error)
+
+// Factory128 builds a 128-bit generator from the parameters of a spec (see New128).
+type Factory128 func(p *Params) (Interface128, error)
+
+var (
+	registryMu  sync.RWMutex
+	registry    = map[string]Factory{}
+	registry128 = map[string]Factory128{}
+)
+
+// Register makes a generator available to New and New128 under name. It panics if name
+// is empty, contains ':' or is already registered, so it is meant to be called from init
+// functions.
+func Register(name string, f Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	checkName("Register", name)
+	registry[name] = f
+}
+
+// Register128 is like Register for 128-bit generators, which are only available to
+// New128.
+func Register128(name string, f Factory128) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	checkName("Register128", name)
+	registry128[name] = f
+}
+
+// checkName panics if name cannot be registered. registryMu must be held.
+func checkName(fn, name string) {
+	if name == "" || strings.Contains(name, ":") {
+		panic(fmt.Sprintf("idgen.%s(%q): invalid name", fn, name))
+	}
+	_, ok := registry[name]
+	_, ok128 := registry128[name]
+	if ok || ok128 {
+		panic(fmt.Sprintf("idgen.%s(%q): already registered", fn, name))
+	}
+}
+
+// Registered returns the sorted names of the registered generators, 128-bit included.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry)+len(registry128))
+	for name := range registry {
+		names = append(names, name)
+	}
+	for name := range registry128 {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// New builds a generator from a spec like "snowflake:node=3,epoch=2020-01-01" or
+// "sequential:start=1000": a registered name, optionally followed by ':' and
+// comma-separated key=value parameters. Unknown parameters are an error, so typos in
+// configuration are caught at startup. Built-in generators:
+//
+//	sequential     start (first ID, default 1), step (default 1)
+//	negsequential
+//	timestamp      unit (e.g. 10ms or 1s, default 1ms)
+//	snowflake      node, epoch (2006-01-02 or RFC 3339), time, nodebits, seq (bit
+//	               widths, default 41/10/12), unit, wait, waitclock (booleans)
+//	jssafe         node, epoch, wait, waitclock
+//	sonyflake      machine
+//
+// 128-bit generators such as "uuidv7" are built by New128 instead.
+func New(spec string) (Interface, error) {
+	name, rest, _ := strings.Cut(spec, ":")
+	registryMu.RLock()
+	f, ok := registry[name]
+	_, ok128 := registry128[name]
+	registryMu.RUnlock()
+	if ok128 {
+		return nil, fmt.Errorf("idgen.New(%q): %q generates 128-bit IDs, use New128", spec, name)
+	} else if !ok {
+		return nil, fmt.Errorf("idgen.New(%q): unknown generator %q", spec, name)
+	}
+	p, err := parseParams(rest)
+	if err != nil {
+		return nil, fmt.Errorf("idgen.New(%q): %w", spec, err)
+	}
+	gen, err := f(p)
+	if err == nil {
+		err = p.checkUsed()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idgen.New(%q): %w", spec, err)
+	}
+	return gen, nil
+}
+
+// New128 is like New for 128-bit IDs. Besides the generators of New, widened (see Widen),
+// the built-in ones are:
+//
+//	uuidv7         (no parameters)
+func New128(spec string) (Interface128, error) {
+	name, rest, _ := strings.Cut(spec, ":")
+	registryMu.RLock()
+	f, ok := registry128[name]
+	registryMu.RUnlock()
+	if !ok {
+		gen, err := New(spec)
+		if err != nil {
+			return nil, err
+		}
+		return Widen(gen), nil
+	}
+	p, err := parseParams(rest)
+	if err != nil {
+		return nil, fmt.Errorf("idgen.New128(%q): %w", spec, err)
+	}
+	gen, err := f(p)
+	if err == nil {
+		err = p.checkUsed()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("idgen.New128(%q): %w", spec, err)
+	}
+	return gen, nil
+}
+
+// parseParams parses the comma-separated key=value parameters of a spec.
+func parseParams(s string) (*Params, error) {
+	p := &Params{values: map[string]string{}, used: map[string]bool{}}
+	if s == "" {
+		return p, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("expected key=value, got %q", kv)
+		}
+		if _, dup := p.values[k]; dup {
+			return nil, fmt.Errorf("duplicate parameter %q", k)
+		}
+		p.values[k] = v
+	}
+	return p, nil
+}
+
+// Params are the key=value parameters of a spec. Reading a parameter marks it as known.
+type Params struct {
+	values map[string]string
+	used   map[string]bool
+}
+
+// checkUsed fails if a parameter was not read, i.e. it is unknown to the generator.
+func (p *Params) checkUsed() error {
+	for k := range p.values {
+		if !p.used[k] {
+			return fmt.Errorf("unknown parameter %q", k)
+		}
+	}
+	return nil
+}
+
+// String returns the parameter key, or def if absent.
+func (p *Params) String(key, def string) string {
+	p.used[key] = true
+	if v, ok := p.values[key]; ok {
+		return v
+	}
+	return def
+}
+
+// Int64 returns the parameter key as an integer, or def if absent.
+func (p *Params) Int64(key string, def int64) (int64, error) {
+	v := p.String(key, "")
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %q: %w", key, err)
+	}
+	return i, nil
+}
+
+// Bool returns the parameter key as a boolean, or false if absent.
+func (p *Params) Bool(key string) (bool, error) {
+	v := p.String(key, "")
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("parameter %q: %w", key, err)
+	}
+	return b, nil
+}
+
+// Duration returns the parameter key as a positive duration (e.g. "10ms"), or def if
+// absent.
+func (p *Params) Duration(key string, def time.Duration) (time.Duration, error) {
+	v := p.String(key, "")
+	if v == "" {
+		return def, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %q: %w", key, err)
+	} else if d <= 0 {
+		return 0, fmt.Errorf("parameter %q: %v must be positive", key, d)
+	}
+	return d, nil
+}
+
+// Time returns the parameter key as a date (2006-01-02) or RFC 3339 time, and whether it
+// was present.
+func (p *Params) Time(key string) (time.Time, bool, error) {
+	v := p.String(key, "")
+	if v == "" {
+		return time.Time{}, false, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339Nano} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("parameter %q: invalid time %q", key, v)
+}
+
+// snowflakeOptions reads the options shared by Snowflake-like specs.
+func (p *Params) snowflakeOptions() ([]Option, error) {
+	var opts []Option
+	epoch, ok, err := p.Time("epoch")
+	if err != nil {
+		return nil, err
+	} else if ok {
+		opts = append(opts, WithEpoch(epoch))
+	}
+	for key, opt := range map[string]Option{
+		"wait":      WithWaitOnOverflow(),
+		"waitclock": WithWaitOnClockRollback(),
+	} {
+		if b, err := p.Bool(key); err != nil {
+			return nil, err
+		} else if b {
+			opts = append(opts, opt)
+		}
+	}
+	return opts, nil
+}
+
+func init() {
+	Register("sequential", func(p *Params) (Interface, error) {
+		start, err := p.Int64("start", 1)
+		if err != nil {
+			return nil, err
+		}
+		step, err := p.Int64("step", 1)
+		if err != nil {
+			return nil, err
+		}
+		if step != 1 {
+			return NewStridedSequential(start, step)
+		}
+		return NewSequentialFrom(start), nil
+	})
+	Register("negsequential", func(p *Params) (Interface, error) {
+		return NewNegSequential(), nil
+	})
+	Register("timestamp", func(p *Params) (Interface, error) {
+		unit, err := p.Duration("unit", time.Millisecond)
+		if err != nil {
+			return nil, err
+		}
+		return NewTimestampUnit(SystemClock, unit), nil
+	})
+	Register("snowflake", func(p *Params) (Interface, error) {
+		var bits [3]int64
+		for i, key := range []string{"time", "nodebits", "seq"} {
+			var err error
+			def := []int64{41, 10, 12}[i]
+			if bits[i], err = p.Int64(key, def); err != nil {
+				return nil, err
+			} else if bits[i] < 0 || bits[i] > 63 {
+				return nil, fmt.Errorf("parameter %q: %d out of range", key, bits[i])
+			}
+		}
+		l, err := NewSnowflakeLayout(byte(bits[0]), byte(bits[1]), byte(bits[2]))
+		if err != nil {
+			return nil, err
+		}
+		if l.Unit, err = p.Duration("unit", time.Millisecond); err != nil {
+			return nil, err
+		}
+		return newSnowflakeSpec(p, l)
+	})
+	Register("jssafe", func(p *Params) (Interface, error) {
+		return newSnowflakeSpec(p, JSSafeLayout)
+	})
+	Register("sonyflake", func(p *Params) (Interface, error) {
+		machine, err := p.Int64("machine", 0)
+		if err != nil {
+			return nil, err
+		} else if machine < 0 || machine > 0xffff {
+			return nil, fmt.Errorf("parameter %q: %d out of range", "machine", machine)
+		}
+		return NewSonyflake(uint16(machine)), nil
+	})
+	Register128("uuidv7", func(p *Params) (Interface128, error) {
+		return Func128(NewUUIDv7), nil
+	})
+}
+
+func newSnowflakeSpec(p *Params, l Layout) (Interface, error) {
+	node, err := p.Int64("node", 0)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := p.snowflakeOptions()
+	if err != nil {
+		return nil, err
+	}
+	return l.NewSnowflakeChecked(node, opts...)
+}
@@ -0,0 +1,151 @@
+package idgen
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		spec string
+		ok   bool
+	}{
+		{"sequential", true},
+		{"sequential:start=1000", true},
+		{"sequential:start=2,step=4", true},
+		{"negsequential", true},
+		{"timestamp", true},
+		{"timestamp:unit=10ms", true},
+		{"snowflake:time=33,seq=20,unit=1s", true},
+		{"snowflake:node=3,epoch=2020-01-01", true},
+		{"snowflake:node=3,epoch=2020-01-01T00:00:00Z,wait=true,waitclock=1", true},
+		{"snowflake:time=39,nodebits=12,seq=12,node=4095,epoch=2024-01-01", true},
+		{"jssafe:node=31", true},
+		{"sonyflake:machine=65535", true},
+		{"uuidv1", false},
+		{"uuidv7", false},
+		{"sequential:start=x", false},
+		{"sequential:begin=1", false},
+		{"sequential:start", false},
+		{"sequential:step=0", false},
+		{"sequential:start=1,start=2", false},
+		{"snowflake:node=1024", false},
+		{"snowflake:time=50,nodebits=10,seq=12", false},
+		{"snowflake:epoch=yesterday", false},
+		{"jssafe:wait=maybe", false},
+		{"sonyflake:machine=65536", false},
+		{"timestamp:unit=0s", false},
+		{"snowflake:unit=fast", false},
+	}
+	for i, test := range tests {
+		gen, err := New(test.spec)
+		if (err == nil) != test.ok {
+			t.Errorf("TestNew %d: got error %v for %q", i, err, test.spec)
+			continue
+		}
+		if err == nil {
+			if _, err := gen.NewIDs(1); err != nil {
+				t.Errorf("TestNew %d: got error %v generating with %q", i, err, test.spec)
+			}
+		}
+	}
+
+	gen, _ := New("sequential:start=1000")
+	if v, _ := gen.NewIDs(1); v != 1000 {
+		t.Errorf("TestNew: got %v, expected 1000", v)
+	}
+	gen, _ = New("sequential:start=2,step=4")
+	if v, _ := gen.NewIDs(2); v != 6 {
+		t.Errorf("TestNew: got %v, expected 6", v)
+	}
+	gen, _ = New("snowflake:node=3,epoch=2020-01-01")
+	v, _ := gen.NewIDs(1)
+	epoch := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if ts, node, _ := SnowflakeLayout.Decompose(v, WithEpoch(epoch)); node != 3 ||
+		time.Since(ts) > time.Minute {
+		t.Errorf("TestNew: got node %v at %v", node, ts)
+	}
+}
+
+func TestNew128(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		spec string
+		ok   bool
+	}{
+		{"uuidv7", true},
+		{"sequential:start=1000", true},
+		{"uuidv7:version=7", false},
+		{"uuidv7:x", false},
+		{"uuidv1", false},
+		{"sequential:start=x", false},
+	}
+	for i, test := range tests {
+		gen, err := New128(test.spec)
+		if (err == nil) != test.ok {
+			t.Errorf("TestNew128 %d: got error %v for %q", i, err, test.spec)
+			continue
+		}
+		if err == nil {
+			if _, err := gen.NewIDs(1); err != nil {
+				t.Errorf("TestNew128 %d: got error %v generating with %q", i, err, test.spec)
+			}
+		}
+	}
+
+	gen, _ := New128("uuidv7")
+	if id, _ := gen.NewIDs(1); id[6]>>4 != 7 {
+		t.Errorf("TestNew128: got %v, expected a version 7 UUID", UUID(id))
+	}
+	gen, _ = New128("sequential:start=1000")
+	if id, _ := gen.NewIDs(1); id[15] != 1000&0xff || id[14] != 1000>>8 {
+		t.Errorf("TestNew128: got %x, expected 1000", id)
+	}
+	if _, err := New("uuidv7"); err == nil || !strings.Contains(err.Error(), "New128") {
+		t.Errorf("TestNew128: got error %v from New, expected a pointer to New128", err)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-constant", func(p *Params) (Interface, error) {
+		v, err := p.Int64("value", 7)
+		return constant(v), err
+	})
+	if !slices.Contains(Registered(), "test-constant") {
+		t.Errorf("TestRegister: got %v, expected test-constant", Registered())
+	}
+	gen, err := New("test-constant:value=9")
+	if v, _ := gen.NewIDs(1); err != nil || v != 9 {
+		t.Errorf("TestRegister: got %v (error %v), expected 9", v, err)
+	}
+	for _, name := range []string{"test-constant", "", "a:b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("TestRegister: expected panic for %q", name)
+				}
+			}()
+			Register(name, nil)
+		}()
+	}
+
+	Register128("test-zero", func(p *Params) (Interface128, error) {
+		return Widen(constant(0)), nil
+	})
+	if !slices.Contains(Registered(), "test-zero") {
+		t.Errorf("TestRegister: got %v, expected test-zero", Registered())
+	}
+	for _, name := range []string{"test-zero", "test-constant", "sequential"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("TestRegister: expected panic for %q", name)
+				}
+			}()
+			Register128(name, nil)
+		}()
+	}
+}
@@ -0,0 +1,101 @@
+package idgen
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+)
+
+// Reserver hands out blocks of IDs in two phases: Reserve sets a Range aside, and the
+// caller either commits it once the IDs are durably used or releases it so they are
+// reserved again later. Outstanding reservations can be listed, so the holes left by
+// callers that failed in between are audited instead of silently leaked.
+// Safe for concurrent use.
+type Reserver struct {
+	gen     Interface
+	mu      sync.Mutex
+	pending map[*Reservation]struct{}
+	free    []Range
+}
+
+// Reservation is a Range pending Commit or Release.
+type Reservation struct {
+	Range
+	r *Reserver
+}
+
+// NewReserver returns a Reserver allocating from gen, which should hand out contiguous
+// blocks (e.g. NewSegment or sqlid.NewHiLo).
+func NewReserver(gen Interface) *Reserver {
+	return &Reserver{gen: gen, pending: map[*Reservation]struct{}{}}
+}
+
+// Reserve sets aside n IDs, preferring released ones to new ones from the generator.
+func (r *Reserver) Reserve(n int64) (*Reservation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rng, ok := r.reuse(n)
+	if !ok {
+		var err error
+		if rng, err = NewIDRange(r.gen, n); err != nil {
+			return nil, err
+		}
+	}
+	res := &Reservation{Range: rng, r: r}
+	r.pending[res] = struct{}{}
+	return res, nil
+}
+
+// reuse takes n IDs from the first released range large enough.
+func (r *Reserver) reuse(n int64) (Range, bool) {
+	for i, f := range r.free {
+		if f.Len() < n || f.Step() != 1 {
+			continue
+		}
+		if f.Len() == n {
+			r.free = slices.Delete(r.free, i, i+1)
+		} else {
+			r.free[i] = NewRange(f.First()+n, f.Len()-n, 1)
+		}
+		return NewRange(f.First(), n, 1), true
+	}
+	return Range{}, false
+}
+
+// Pending returns the ranges reserved but neither committed nor released, ordered by
+// first ID.
+func (r *Reserver) Pending() []Range {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ranges := make([]Range, 0, len(r.pending))
+	for res := range r.pending {
+		ranges = append(ranges, res.Range)
+	}
+	slices.SortFunc(ranges, func(a, b Range) int {
+		return cmp.Compare(a.First(), b.First())
+	})
+	return ranges
+}
+
+// Commit marks the IDs as used.
+func (res *Reservation) Commit() error {
+	return res.r.done(res, false)
+}
+
+// Release returns the IDs to the Reserver, to be reserved again.
+func (res *Reservation) Release() error {
+	return res.r.done(res, true)
+}
+
+func (r *Reserver) done(res *Reservation, release bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.pending[res]; !ok {
+		return ErrReservationDone
+	}
+	delete(r.pending, res)
+	if release {
+		r.free = append(r.free, res.Range)
+	}
+	return nil
+}
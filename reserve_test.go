@@ -0,0 +1,51 @@
+package idgen
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestReserver(t *testing.T) {
+	t.Parallel()
+	r := NewReserver(NewSequential())
+	a, _ := r.Reserve(3)
+	b, _ := r.Reserve(2)
+	c, _ := r.Reserve(4)
+	if a.First() != 1 || b.First() != 4 || c.Last() != 9 {
+		t.Errorf("TestReserver: got %+v %+v %+v", a.Range, b.Range, c.Range)
+	}
+	if err := a.Release(); err != nil {
+		t.Errorf("TestReserver: got error %v", err)
+	}
+	if err := b.Commit(); err != nil {
+		t.Errorf("TestReserver: got error %v", err)
+	}
+	for _, err := range []error{a.Commit(), b.Release()} {
+		if err != ErrReservationDone {
+			t.Errorf("TestReserver: got error %v, expected %v", err, ErrReservationDone)
+		}
+	}
+	if p := r.Pending(); !slices.Equal(p, []Range{c.Range}) {
+		t.Errorf("TestReserver: got pending %v, expected %v", p, []Range{c.Range})
+	}
+
+	var tests = []struct {
+		count, first int64
+	}{
+		{2, 1},  // from released [1, 3]
+		{2, 10}, // only 1 ID left in [3, 3]
+		{1, 3},
+		{1, 12},
+	}
+	for i, test := range tests {
+		res, err := r.Reserve(test.count)
+		if err != nil || res.First() != test.first || res.Len() != test.count {
+			t.Errorf("TestReserver %d: got %+v (error %v), expected first %v",
+				i, res, err, test.first)
+		}
+	}
+	if _, err := NewReserver(broken{errTest}).Reserve(1); !errors.Is(err, errTest) {
+		t.Errorf("TestReserver: got error %v, expected %v", err, errTest)
+	}
+}
@@ -0,0 +1,170 @@
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// SegmentStore reserves segments of IDs from an authoritative backend (e.g. a database
+// counter), for NewSegment.
+type SegmentStore interface {
+	// Reserve reserves n contiguous IDs, returning the last one (so the first is
+	// last-n+1), like Interface.NewIDs.
+	Reserve(ctx context.Context, n int64) (int64, error)
+}
+
+// SegmentStoreFunc adapts a function to SegmentStore.
+type SegmentStoreFunc func(ctx context.Context, n int64) (int64, error)
+
+// Reserve implements SegmentStore.
+func (f SegmentStoreFunc) Reserve(ctx context.Context, n int64) (int64, error) {
+	return f(ctx, n)
+}
+
+// segment is a range of size reserved IDs, last is the last one handed out.
+type segment struct {
+	last, limit, size int64
+}
+
+// segmentAllocator implements the double-buffered allocation of NewSegment.
+type segmentAllocator struct {
+	store     SegmentStore
+	size      int64
+	adaptive  *AdaptiveBlock
+	threshold float64
+
+	mu  sync.Mutex
+	cur segment
+	// next, if not nil, was prefetched. While fetching, fetched is closed when done.
+	next     *segment
+	fetching bool
+	fetched  chan struct{}
+	err      error
+	closed   bool
+}
+
+// NewSegment returns an ID generator handing out IDs from segments of size IDs reserved
+// from store, like Meituan's Leaf. When the current segment is consumed past threshold
+// (a fraction between 0 and 1, e.g. 0.2), the next segment is fetched in the background,
+// so under steady load NewIDs never waits for the store. IDs left in a segment are
+// skipped when the process exits, and when a request does not fit in the rest of the
+// current segment (ranges must be contiguous), so n can be at most size. The generator
+// implements io.Closer to stop background fetches. Safe for concurrent use.
+func NewSegment(store SegmentStore, size int64, threshold float64) (Interface, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("NewSegment(): size must be positive, got %d", size)
+	}
+	if threshold < 0 || threshold > 1 {
+		return nil, fmt.Errorf("NewSegment(): threshold must be between 0 and 1, got %v", threshold)
+	}
+	return &segmentAllocator{store: store, size: size, threshold: threshold}, nil
+}
+
+// NewAdaptiveSegment is like NewSegment, with segments sized by block from the
+// consumption rate. n can be at most the largest size of block.
+func NewAdaptiveSegment(store SegmentStore, block *AdaptiveBlock, threshold float64) (Interface, error) {
+	s, err := NewSegment(store, block.max, threshold)
+	if err != nil {
+		return nil, err
+	}
+	s.(*segmentAllocator).adaptive = block
+	return s, nil
+}
+
+// nextSize returns the size of the next segment to reserve, at least n.
+func (s *segmentAllocator) nextSize(n int64) int64 {
+	if s.adaptive == nil {
+		return s.size
+	}
+	return max(s.adaptive.Next(), n)
+}
+
+func (s *segmentAllocator) NewIDs(n int64) (int64, error) {
+	return s.NewIDsContext(context.Background(), n)
+}
+
+// NewIDsContext is like NewIDs, giving up waiting for a background fetch when ctx is
+// done and passing ctx to the store when a segment must be fetched synchronously. Errors
+// for deadlines are ErrDeadline.
+func (s *segmentAllocator) NewIDsContext(ctx context.Context, n int64) (int64, error) {
+	if n < 1 || n > s.size {
+		return 0, unsupportedCount(s, n)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.cur.limit-s.cur.last < n || s.closed {
+		if s.closed {
+			return 0, ErrClosed
+		}
+		if s.fetching {
+			if err := s.waitFetch(ctx); err != nil {
+				return 0, err
+			}
+			// Others may have taken the segment or closed s meanwhile.
+			continue
+		}
+		if s.next != nil && s.next.limit-s.next.last < n {
+			// Prefetched with a smaller adaptive size: skipped like the rest of cur.
+			s.next = nil
+		}
+		if s.next == nil {
+			// Not prefetched yet (or the prefetch failed or was too small): fetch
+			// synchronously.
+			s.err = nil
+			size := s.nextSize(n)
+			limit, err := s.store.Reserve(ctx, size)
+			if err != nil {
+				return 0, contextError(err)
+			}
+			s.next = &segment{last: limit - size, limit: limit, size: size}
+		}
+		s.cur, s.next = *s.next, nil
+	}
+	s.cur.last += n
+	if used := s.cur.size - (s.cur.limit - s.cur.last); used >= int64(s.threshold*float64(s.cur.size)) &&
+		s.next == nil && !s.fetching && s.err == nil {
+		s.fetching, s.fetched = true, make(chan struct{})
+		go s.prefetch(s.nextSize(1))
+	}
+	return s.cur.last, nil
+}
+
+// waitFetch waits for the background fetch, unlocking s meanwhile, or until ctx is done.
+func (s *segmentAllocator) waitFetch(ctx context.Context) error {
+	fetched := s.fetched
+	s.mu.Unlock()
+	defer s.mu.Lock()
+	select {
+	case <-fetched:
+		return nil
+	case <-ctx.Done():
+		return contextError(ctx.Err())
+	}
+}
+
+// Close implements io.Closer, waiting for a background fetch to finish. The rest of the
+// reserved segments is skipped. NewIDs returns ErrClosed afterwards.
+func (s *segmentAllocator) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for s.fetching {
+		s.waitFetch(context.Background())
+	}
+	return nil
+}
+
+func (s *segmentAllocator) prefetch(size int64) {
+	limit, err := s.store.Reserve(context.Background(), size)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		// Retried synchronously when the current segment is exhausted.
+		s.err = err
+	} else {
+		s.next = &segment{last: limit - size, limit: limit, size: size}
+	}
+	s.fetching = false
+	close(s.fetched)
+}
@@ -0,0 +1,128 @@
+package idgen
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// segmentStore is a SegmentStore that can block and fail on demand.
+type segmentStore struct {
+	sync.Mutex
+	value, calls int64
+	err          error
+	gate         chan struct{}
+}
+
+func (s *segmentStore) Reserve(ctx context.Context, n int64) (int64, error) {
+	if s.gate != nil {
+		<-s.gate
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	s.value += n
+	return s.value, nil
+}
+
+func TestSegment(t *testing.T) {
+	t.Parallel()
+	for _, test := range []struct {
+		size      int64
+		threshold float64
+	}{{0, 0.5}, {10, -0.1}, {10, 1.1}} {
+		if _, err := NewSegment(&segmentStore{}, test.size, test.threshold); err == nil {
+			t.Errorf("TestSegment %v: expected error", test)
+		}
+	}
+
+	store := &segmentStore{}
+	gen, err := NewSegment(store, 10, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		count, expected int64
+	}{
+		{1, 1},
+		{4, 5}, // prefetch starts
+		{5, 10},
+		{1, 11},
+		{8, 19},
+		{3, 23}, // 1 left: skipped
+	}
+	for i, test := range tests {
+		if v, err := gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("TestSegment %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	for _, n := range []int64{0, 11} {
+		if v, err := gen.NewIDs(n); err == nil {
+			t.Errorf("TestSegment: expected error for count %d, got %v", n, v)
+		}
+	}
+}
+
+func TestSegmentPrefetch(t *testing.T) {
+	t.Parallel()
+	store := &segmentStore{}
+	gen, _ := NewSegment(store, 10, 0.5)
+	if v, err := gen.NewIDs(2); err != nil || v != 2 {
+		t.Fatalf("TestSegmentPrefetch: got %v (error %v), expected 2", v, err)
+	}
+	// The prefetch blocks in the store, IDs are still available.
+	store.gate = make(chan struct{})
+	if v, err := gen.NewIDs(8); err != nil || v != 10 {
+		t.Errorf("TestSegmentPrefetch: got %v (error %v), expected 10", v, err)
+	}
+	close(store.gate)
+	if v, err := gen.NewIDs(1); err != nil || v != 11 {
+		t.Errorf("TestSegmentPrefetch: got %v (error %v), expected 11", v, err)
+	}
+
+	// A failed prefetch is retried when needed.
+	gen, _ = NewSegment(store, 10, 0)
+	store.Lock()
+	store.err = errTest
+	store.Unlock()
+	if _, err := gen.NewIDs(1); !errors.Is(err, errTest) {
+		t.Errorf("TestSegmentPrefetch: got error %v, expected %v", err, errTest)
+	}
+	store.Lock()
+	store.err = nil
+	store.Unlock()
+	if v, err := gen.NewIDs(1); err != nil || v != 21 {
+		t.Errorf("TestSegmentPrefetch: got %v (error %v), expected 21", v, err)
+	}
+}
+
+func TestSegmentClose(t *testing.T) {
+	t.Parallel()
+	store := &segmentStore{gate: make(chan struct{})}
+	gen, _ := NewSegment(store, 10, 0)
+	go func() { store.gate <- struct{}{} }()
+	gen.NewIDs(1)
+	// The prefetch blocks in the store, so Close waits for it.
+	closed := make(chan error)
+	go func() { closed <- Close(gen) }()
+	select {
+	case <-closed:
+		t.Errorf("TestSegmentClose: Close returned during a fetch")
+	case <-time.After(10 * time.Millisecond):
+	}
+	close(store.gate)
+	if err := <-closed; err != nil {
+		t.Errorf("TestSegmentClose: got error %v", err)
+	}
+	if _, err := gen.NewIDs(1); err != ErrClosed {
+		t.Errorf("TestSegmentClose: got error %v, expected %v", err, ErrClosed)
+	}
+	if err := Close(NewSequential()); err != nil {
+		t.Errorf("TestSegmentClose: got error %v closing a generator without Close", err)
+	}
+}
@@ -0,0 +1,30 @@
+package idgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortableLen is the length of strings produced by EncodeSortable.
+const SortableLen = 11
+
+// EncodeSortable returns v in base62, zero-padded to SortableLen characters, so that
+// comparing the strings byte by byte (e.g. as keys in an ordered store) gives the same
+// order as comparing the values. The sign bit is flipped before encoding, so negative
+// values sort first; 0 is "AzL8n0Y58m8".
+func EncodeSortable(v int64) string {
+	s := encodeBase(uint64(v)^1<<63, base62)
+	return strings.Repeat("0", SortableLen-len(s)) + s
+}
+
+// DecodeSortable decodes a value encoded by EncodeSortable.
+func DecodeSortable(s string) (int64, error) {
+	if len(s) != SortableLen {
+		return 0, fmt.Errorf("DecodeSortable(%q): expected %d characters", s, SortableLen)
+	}
+	u, err := decodeBase(s, base62)
+	if err != nil {
+		return 0, fmt.Errorf("DecodeSortable(%q): %v", s, err)
+	}
+	return int64(u ^ 1<<63), nil
+}
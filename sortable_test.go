@@ -0,0 +1,53 @@
+package idgen
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSortable(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		v int64
+		s string
+	}{
+		{-1 << 63, "00000000000"},
+		{0, "AzL8n0Y58m8"},
+		{1<<63 - 1, "LygHa16AHYF"},
+	}
+	for i, test := range tests {
+		if s := EncodeSortable(test.v); s != test.s {
+			t.Errorf("TestSortable %d: got %q, expected %q", i, s, test.s)
+		}
+		if v, err := DecodeSortable(test.s); err != nil || v != test.v {
+			t.Errorf("TestSortable %d: got %v (error %v), expected %v", i, v, err, test.v)
+		}
+	}
+	for _, s := range []string{"", "0", "AzL8n0Y58m", "AzL8n0Y58m8_", "zzzzzzzzzzz", "AzL8n0Y58m-"} {
+		if v, err := DecodeSortable(s); err == nil {
+			t.Errorf("TestSortable %q: got %v, expected error", s, v)
+		}
+	}
+
+	r := rand.New(rand.NewSource(1))
+	values := []int64{-1 << 63, -1, 0, 1, 61, 62, 1<<63 - 1}
+	for range 1000 {
+		values = append(values, int64(r.Uint64()), r.Int63n(1<<40))
+	}
+	encoded := make([]string, len(values))
+	for i, v := range values {
+		encoded[i] = EncodeSortable(v)
+		if len(encoded[i]) != SortableLen {
+			t.Errorf("TestSortable: got %q for %v", encoded[i], v)
+		}
+	}
+	slices.Sort(values)
+	slices.SortFunc(encoded, strings.Compare)
+	for i, s := range encoded {
+		if v, _ := DecodeSortable(s); v != values[i] {
+			t.Errorf("TestSortable %d: got %v, expected %v", i, v, values[i])
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package sqlid
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/carloslenz/idgen"
+)
+
+// Counter is a counter row in the database, usable as an idgen.SegmentStore.
+type Counter struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+	name    string
+}
+
+var _ idgen.SegmentStore = (*Counter)(nil)
+
+// NewCounter returns the Counter for row name in table (see Dialect).
+func NewCounter(db *sql.DB, dialect Dialect, table, name string) *Counter {
+	return &Counter{db: db, dialect: dialect, table: table, name: name}
+}
+
+// Reserve implements idgen.SegmentStore.
+func (c *Counter) Reserve(ctx context.Context, n int64) (int64, error) {
+	return c.dialect.AddAndGet(ctx, c.db, c.table, c.name, n)
+}
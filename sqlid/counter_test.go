@@ -0,0 +1,20 @@
+package sqlid
+
+import (
+	"testing"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestCounter(t *testing.T) {
+	db := openDB(t)
+	gen, err := idgen.NewSegment(NewCounter(db, SQLite, "counters", "orders"), 10, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []int64{101, 102, 103} {
+		if v, err := gen.NewIDs(1); err != nil || v != expected {
+			t.Errorf("%d: got %v (error %v), expected %v", i, v, err, expected)
+		}
+	}
+}
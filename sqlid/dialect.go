@@ -0,0 +1,104 @@
+// Package sqlid implements ID generators backed by SQL databases, for systems where the
+// database must stay authoritative for uniqueness.
+package sqlid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/carloslenz/idgen"
+)
+
+// Dialect adapts the statements used by the generators to a database. Table names are
+// interpolated into the statements, so they must come from trusted configuration.
+type Dialect interface {
+	// AddAndGet atomically adds n to the counter of the row name in table, returning
+	// the new value. The table has the columns name (primary key) and value (BIGINT).
+	AddAndGet(ctx context.Context, db *sql.DB, table, name string, n int64) (int64, error)
+	// NextVal returns the next value of the database sequence.
+	NextVal(ctx context.Context, db *sql.DB, sequence string) (int64, error)
+}
+
+var (
+	// Postgres uses UPDATE ... RETURNING with $n placeholders, and native sequences.
+	Postgres Dialect = returning{
+		placeholders: [2]string{"$1", "$2"},
+		nextval:      "SELECT nextval($1)",
+	}
+	// SQLite uses UPDATE ... RETURNING (SQLite 3.35 and later). Sequences are not
+	// supported.
+	SQLite Dialect = returning{placeholders: [2]string{"?", "?"}}
+	// MySQL uses LAST_INSERT_ID(expr) for counters. Sequences are emulated with
+	// Flickr-style ticket tables, with an auto-increment primary key and a unique stub
+	// column:
+	//
+	//	CREATE TABLE tickets (
+	//		id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+	//		stub CHAR(1) NOT NULL UNIQUE
+	//	)
+	MySQL Dialect = mysql{}
+)
+
+// returning implements Dialect for databases supporting UPDATE ... RETURNING.
+type returning struct {
+	placeholders [2]string
+	// nextval is the query for sequences, empty if not supported.
+	nextval string
+}
+
+func (r returning) AddAndGet(ctx context.Context, db *sql.DB, table, name string, n int64) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET value = value + %s WHERE name = %s RETURNING value",
+		table, r.placeholders[0], r.placeholders[1])
+	var v int64
+	err := db.QueryRowContext(ctx, query, n, name).Scan(&v)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("sqlid: counter %q not found in %s", name, table)
+	}
+	return v, err
+}
+
+func (r returning) NextVal(ctx context.Context, db *sql.DB, sequence string) (int64, error) {
+	if r.nextval == "" {
+		return 0, fmt.Errorf("sqlid: sequences are not supported by this dialect")
+	}
+	var v int64
+	err := db.QueryRowContext(ctx, r.nextval, sequence).Scan(&v)
+	return v, err
+}
+
+// mysql implements Dialect for MySQL, relying on the last insert ID returned with the
+// statement result (which is per connection).
+type mysql struct{}
+
+func (mysql) AddAndGet(ctx context.Context, db *sql.DB, table, name string, n int64) (int64, error) {
+	query := fmt.Sprintf("UPDATE %s SET value = LAST_INSERT_ID(value + ?) WHERE name = ?", table)
+	res, err := db.ExecContext(ctx, query, n, name)
+	if err != nil {
+		return 0, err
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return 0, err
+	} else if rows == 0 {
+		return 0, fmt.Errorf("sqlid: counter %q not found in %s", name, table)
+	}
+	return res.LastInsertId()
+}
+
+func (mysql) NextVal(ctx context.Context, db *sql.DB, sequence string) (int64, error) {
+	res, err := db.ExecContext(ctx, fmt.Sprintf("REPLACE INTO %s (stub) VALUES ('a')", sequence))
+	if err != nil {
+		return 0, err
+	}
+	return res.LastInsertId()
+}
+
+// contextError wraps database errors for passed deadlines with idgen.ErrDeadline, like
+// the generators of package idgen.
+func contextError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %v", idgen.ErrDeadline, err)
+	}
+	return err
+}
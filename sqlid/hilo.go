@@ -0,0 +1,77 @@
+package sqlid
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+)
+
+// HiLo reserves blocks of IDs from a counter row in the database, with a single
+// statement per block, and hands them out from memory. Uniqueness is guaranteed by the
+// database even with many processes, with far fewer round-trips than one per ID. IDs
+// left in a block are skipped when the process exits, and when a request does not fit
+// in the rest of the current block (ranges must be contiguous). Safe for concurrent use.
+type HiLo struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+	name    string
+	block   int64
+	// adaptive, if set, sizes the blocks instead of block.
+	adaptive *idgen.AdaptiveBlock
+
+	mu sync.Mutex
+	// last is the last ID handed out and limit the last ID of the current block.
+	last, limit int64
+}
+
+var _ idgen.Interface = (*HiLo)(nil)
+
+// NewHiLo returns a HiLo generator reserving blocks of block IDs from the counter row
+// name in table. The row must exist: its value is the last reserved ID, so the first ID
+// will be value+1.
+func NewHiLo(db *sql.DB, dialect Dialect, table, name string, block int64) (*HiLo, error) {
+	if block < 1 {
+		return nil, fmt.Errorf("sqlid.NewHiLo(%q): block must be positive, got %d", name, block)
+	}
+	return &HiLo{db: db, dialect: dialect, table: table, name: name, block: block}, nil
+}
+
+// NewAdaptiveHiLo is like NewHiLo, with blocks sized by block from the consumption rate.
+func NewAdaptiveHiLo(db *sql.DB, dialect Dialect, table, name string, block *idgen.AdaptiveBlock) *HiLo {
+	return &HiLo{db: db, dialect: dialect, table: table, name: name, adaptive: block}
+}
+
+// NewIDs implements idgen.Interface.
+func (h *HiLo) NewIDs(n int64) (int64, error) {
+	return h.NewIDsContext(context.Background(), n)
+}
+
+// NewIDsContext is like NewIDs, using ctx for database access. Errors for deadlines are
+// idgen.ErrDeadline.
+func (h *HiLo) NewIDsContext(ctx context.Context, n int64) (int64, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("%T.NewIDs() supports count>=1, got %v: %w", h, n, idgen.ErrUnsupportedCount)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.limit-h.last < n {
+		size := h.block
+		if h.adaptive != nil {
+			size = h.adaptive.Next()
+		}
+		if n > size {
+			size = n
+		}
+		limit, err := h.dialect.AddAndGet(ctx, h.db, h.table, h.name, size)
+		if err != nil {
+			return 0, contextError(err)
+		}
+		h.last, h.limit = limit-size, limit
+	}
+	h.last += n
+	return h.last, nil
+}
@@ -0,0 +1,80 @@
+package sqlid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+func TestHiLo(t *testing.T) {
+	db := openDB(t)
+	if _, err := NewHiLo(db, SQLite, "counters", "orders", 0); err == nil {
+		t.Errorf("expected error for block 0")
+	}
+	a, err1 := NewHiLo(db, SQLite, "counters", "orders", 10)
+	b, err2 := NewHiLo(db, SQLite, "counters", "orders", 10)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("got errors %v, %v", err1, err2)
+	}
+	var tests = []struct {
+		gen             *HiLo
+		count, expected int64
+	}{
+		{a, 1, 101},
+		{b, 1, 111},
+		{a, 9, 110},
+		{a, 1, 121},
+		{b, 5, 116},
+		{b, 5, 135}, // 4 left in the block: skipped
+		{a, 15, 155},
+	}
+	for i, test := range tests {
+		if v, err := test.gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("%d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	if v, err := a.NewIDs(0); err == nil {
+		t.Errorf("expected error for count 0, got %v", v)
+	}
+	missing, _ := NewHiLo(db, SQLite, "counters", "missing", 10)
+	if v, err := missing.NewIDs(1); err == nil {
+		t.Errorf("expected error for missing counter, got %v", v)
+	}
+}
+
+func TestAdaptiveHiLo(t *testing.T) {
+	db := openDB(t)
+	block, _ := idgen.NewAdaptiveBlock(10, 40, time.Hour)
+	gen := NewAdaptiveHiLo(db, SQLite, "counters", "orders", block)
+	// Blocks are used up quickly: they grow to 20 and 40 IDs.
+	var tests = []struct {
+		count, expected int64
+	}{
+		{10, 110},
+		{1, 111},
+		{19, 130},
+		{1, 131},
+		{50, 220}, // Larger than any block: the rest of the current one is skipped.
+	}
+	for i, test := range tests {
+		if v, err := gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("%d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	if block.Size() != 40 {
+		t.Errorf("got block size %d, expected 40", block.Size())
+	}
+}
+
+func TestHiLoDeadline(t *testing.T) {
+	db := openDB(t)
+	gen, _ := NewHiLo(db, SQLite, "counters", "orders", 10)
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	if v, err := gen.NewIDsContext(ctx, 1); !errors.Is(err, idgen.ErrDeadline) {
+		t.Errorf("got %v (error %v), expected %v", v, err, idgen.ErrDeadline)
+	}
+}
@@ -0,0 +1,64 @@
+package sqlid
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/carloslenz/idgen"
+)
+
+// Sequence generates IDs from a database sequence (nextval in Postgres, a ticket table in
+// MySQL), keeping the database as the source of truth. Each value v returned by the
+// sequence reserves the block [v, v+increment-1], so the sequence must be configured to
+// advance by increment (INCREMENT BY in Postgres, auto_increment_increment in MySQL),
+// and handed out from memory. IDs left in a block are skipped when the process exits,
+// and when a request does not fit in the rest of the current block. Safe for concurrent
+// use.
+type Sequence struct {
+	db        *sql.DB
+	dialect   Dialect
+	name      string
+	increment int64
+
+	mu sync.Mutex
+	// last is the last ID handed out and limit the last ID of the current block.
+	last, limit int64
+}
+
+var _ idgen.Interface = (*Sequence)(nil)
+
+// NewSequence returns a Sequence generator for the database sequence name.
+func NewSequence(db *sql.DB, dialect Dialect, name string, increment int64) (*Sequence, error) {
+	if increment < 1 {
+		return nil, fmt.Errorf("sqlid.NewSequence(%q): increment must be positive, got %d",
+			name, increment)
+	}
+	return &Sequence{db: db, dialect: dialect, name: name, increment: increment}, nil
+}
+
+// NewIDs implements idgen.Interface. The count can be at most increment.
+func (s *Sequence) NewIDs(n int64) (int64, error) {
+	return s.NewIDsContext(context.Background(), n)
+}
+
+// NewIDsContext is like NewIDs, using ctx for database access. Errors for deadlines are
+// idgen.ErrDeadline.
+func (s *Sequence) NewIDsContext(ctx context.Context, n int64) (int64, error) {
+	if n < 1 || n > s.increment {
+		return 0, fmt.Errorf("%T.NewIDs() supports count between 1 and %d, got %v: %w",
+			s, s.increment, n, idgen.ErrUnsupportedCount)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.limit-s.last < n {
+		v, err := s.dialect.NextVal(ctx, s.db, s.name)
+		if err != nil {
+			return 0, contextError(err)
+		}
+		s.last, s.limit = v-1, v+s.increment-1
+	}
+	s.last += n
+	return s.last, nil
+}
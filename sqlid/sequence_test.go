@@ -0,0 +1,53 @@
+package sqlid
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+// sequenceDialect emulates a sequence with a counter, showing Dialect is pluggable.
+type sequenceDialect struct {
+	Dialect
+	increment int64
+}
+
+func (d sequenceDialect) NextVal(ctx context.Context, db *sql.DB, sequence string) (int64, error) {
+	v, err := d.AddAndGet(ctx, db, "counters", sequence, d.increment)
+	return v - d.increment + 1, err
+}
+
+func TestSequence(t *testing.T) {
+	db := openDB(t)
+	dialect := sequenceDialect{SQLite, 10}
+	if _, err := NewSequence(db, dialect, "orders", 0); err == nil {
+		t.Errorf("expected error for increment 0")
+	}
+	gen, err := NewSequence(db, dialect, "orders", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests = []struct {
+		count, expected int64
+	}{
+		{1, 101},
+		{9, 110},
+		{5, 115},
+		{6, 126}, // 5 left: skipped
+	}
+	for i, test := range tests {
+		if v, err := gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("%d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	for _, n := range []int64{0, 11} {
+		if v, err := gen.NewIDs(n); err == nil {
+			t.Errorf("expected error for count %d, got %v", n, v)
+		}
+	}
+
+	gen, _ = NewSequence(db, SQLite, "orders", 10)
+	if v, err := gen.NewIDs(1); err == nil {
+		t.Errorf("expected error for unsupported dialect, got %v", v)
+	}
+}
@@ -0,0 +1,69 @@
+package idgen
+
+import (
+	"expvar"
+	"time"
+)
+
+// snowflakeStats are the counters published by WithExpvar. Methods are no-ops on nil, so
+// generators without stats pay a nil check.
+type snowflakeStats struct {
+	issued, overflows, regressions, lastTimestamp *expvar.Int
+	// shift, unit and epoch convert generated timestamps to Unix Milliseconds.
+	shift       byte
+	unit, epoch int64
+}
+
+// WithExpvar publishes stats of the generator as an expvar.Map named name, for
+// deployments without Prometheus (see package idgenprom): "issued" IDs,
+// "last_timestamp" in Unix Milliseconds, sequence "overflows" and "clock_regressions",
+// the latter two counted once per NewIDs call. Generators given the same name share
+// the map.
+func WithExpvar(name string) Option {
+	return func(o *options) {
+		o.expvar = name
+	}
+}
+
+func newSnowflakeStats(name string, l Layout, epoch int64) *snowflakeStats {
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		// Panics if name is published with another type, like expvar.Publish.
+		m = expvar.NewMap(name)
+	}
+	s := &snowflakeStats{shift: l.NodeBits + l.SeqBits, unit: int64(l.unit()), epoch: epoch}
+	for _, v := range []struct {
+		key string
+		v   **expvar.Int
+	}{
+		{"issued", &s.issued},
+		{"last_timestamp", &s.lastTimestamp},
+		{"overflows", &s.overflows},
+		{"clock_regressions", &s.regressions},
+	} {
+		if *v.v, ok = m.Get(v.key).(*expvar.Int); !ok {
+			*v.v = new(expvar.Int)
+			m.Set(v.key, *v.v)
+		}
+	}
+	return s
+}
+
+func (s *snowflakeStats) issue(n, tstamp int64) {
+	if s != nil {
+		s.issued.Add(n)
+		s.lastTimestamp.Set((int64(uint64(tstamp)>>s.shift)*s.unit + s.epoch) / int64(time.Millisecond))
+	}
+}
+
+func (s *snowflakeStats) overflow() {
+	if s != nil {
+		s.overflows.Add(1)
+	}
+}
+
+func (s *snowflakeStats) regression() {
+	if s != nil {
+		s.regressions.Add(1)
+	}
+}
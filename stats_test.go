@@ -0,0 +1,41 @@
+package idgen
+
+import (
+	"expvar"
+	"testing"
+	"time"
+)
+
+func TestWithExpvar(t *testing.T) {
+	t.Parallel()
+	now := int64(5 * time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	gen := NewSnowflake(1, WithClock(clock), WithExpvar("idgen_test_snowflake"))
+	for _, step := range []struct {
+		count, advance int64
+	}{
+		{1, 0},
+		{4096, 0},
+		{1, -int64(time.Millisecond)},
+		{2, int64(2 * time.Millisecond)},
+	} {
+		now += step.advance
+		gen.NewIDs(step.count)
+	}
+	m := expvar.Get("idgen_test_snowflake").(*expvar.Map)
+	for key, expected := range map[string]string{
+		"issued":            "3",
+		"last_timestamp":    "6",
+		"overflows":         "1",
+		"clock_regressions": "1",
+	} {
+		if v := m.Get(key); v == nil || v.String() != expected {
+			t.Errorf("TestWithExpvar: got %s=%v, expected %v", key, v, expected)
+		}
+	}
+	// Same name shares the counters.
+	NewSnowflake(1, WithExpvar("idgen_test_snowflake")).NewIDs(1)
+	if v := m.Get("issued").String(); v != "4" {
+		t.Errorf("TestWithExpvar: got issued=%v, expected 4", v)
+	}
+}
@@ -0,0 +1,75 @@
+package idgen
+
+import (
+	"fmt"
+	"math"
+)
+
+// strided hands out start, start+step, start+2*step... counting IDs with seq from
+// math.MinInt64, so that up to 2^64-1 of them fit. step is negative for descending ranges.
+type strided struct {
+	seq         sequential
+	start, step int64
+}
+
+// newStrided returns a strided generator whose last ID is the one at index last (from
+// 0), capped to issue at most 2^64-1 IDs.
+func newStrided(start, step int64, last uint64) *strided {
+	s := &strided{start: start, step: step}
+	s.seq.value = math.MinInt64
+	s.seq.ceiling, s.seq.bounded = int64(1<<63+min(last, math.MaxUint64-1)+1), true
+	return s
+}
+
+// NewStridedSequential returns an ID generator like NewSequentialFrom whose IDs are step
+// apart, starting at start. Writers that cannot coordinate can share an ID space by
+// interleaving: with the same step K and starts 1 to K (or any starts distinct modulo K),
+// like MySQL's auto_increment_increment and auto_increment_offset in multi-master
+// setups. Batches are not contiguous, see NewIDRange. Safe for concurrent use.
+func NewStridedSequential(start, step int64) (Interface, error) {
+	if step < 1 {
+		return nil, fmt.Errorf("NewStridedSequential(%d, %d): step must be positive", start, step)
+	}
+	// The k-th ID is start+(k-1)*step, so k-1 must not exceed this for it to fit.
+	return newStrided(start, step, uint64(math.MaxInt64-start)/uint64(step)), nil
+}
+
+// NewNegSequentialRange returns an ID generator like NewNegSequential confined to the IDs
+// from floor to ceiling, counting down from ceiling if descending, failing with
+// ErrExhausted once they are all used. Migration jobs can then be given disjoint ranges
+// (e.g. one negative range per dataset) and stop instead of bleeding into each other's.
+// Descending batches are not contiguous upwards, see NewIDRange. At most 2^64-1 IDs are
+// issued, so the whole int64 range stops one short (at math.MaxInt64-1 ascending,
+// math.MinInt64+1 descending). Safe for concurrent use.
+func NewNegSequentialRange(floor, ceiling int64, descending bool) (Interface, error) {
+	if floor > ceiling {
+		return nil, fmt.Errorf("NewNegSequentialRange(%d, %d): floor must not exceed ceiling",
+			floor, ceiling)
+	}
+	if descending {
+		return newStrided(ceiling, -1, uint64(ceiling-floor)), nil
+	}
+	return newStrided(floor, 1, uint64(ceiling-floor)), nil
+}
+
+func (s *strided) NewIDs(n int64) (int64, error) {
+	if n < 1 {
+		return 0, unsupportedCount(s, n)
+	}
+	v, err := s.seq.NewIDs(n)
+	if err != nil {
+		return 0, err
+	}
+	// The k-th ID, modulo 2^64 since the intermediate values may not fit in int64.
+	k := uint64(v) - 1<<63
+	return int64(uint64(s.start) + (k-1)*uint64(s.step)), nil
+}
+
+// NewIDRange implements RangeInterface.
+func (s *strided) NewIDRange(n int64) (Range, error) {
+	last, err := s.NewIDs(n)
+	if err != nil {
+		return Range{}, err
+	}
+	return NewRange(last-(n-1)*s.step, n, s.step), nil
+}
@@ -0,0 +1,97 @@
+package idgen
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestStridedSequential(t *testing.T) {
+	t.Parallel()
+	if _, err := NewStridedSequential(1, 0); err == nil {
+		t.Error("TestStridedSequential: expected error for step 0")
+	}
+	odd, _ := NewStridedSequential(1, 2)
+	even, _ := NewStridedSequential(2, 2)
+	var tests = []struct {
+		gen             Interface
+		count, expected int64
+	}{
+		{odd, 1, 1},
+		{even, 1, 2},
+		{odd, 1, 3},
+		{odd, 3, 9}, // 5, 7, 9.
+		{even, 2, 6},
+	}
+	for i, test := range tests {
+		if v, err := test.gen.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("TestStridedSequential %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	if _, err := odd.NewIDs(0); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestStridedSequential: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+
+	gen, _ := NewStridedSequential(3, 10)
+	gen.NewIDs(1)
+	r, err := NewIDRange(gen, 3)
+	if err != nil || r.First() != 13 || r.Last() != 33 || r.Step() != 10 {
+		t.Errorf("TestStridedSequential: got %+v (error %v)", r, err)
+	}
+
+	gen, _ = NewStridedSequential(math.MaxInt64-25, 10)
+	if v, err := gen.NewIDs(3); err != nil || v != math.MaxInt64-5 {
+		t.Errorf("TestStridedSequential: got %v (error %v), expected %v", v, err, int64(math.MaxInt64-5))
+	}
+	if v, err := gen.NewIDs(1); !errors.Is(err, ErrExhausted) {
+		t.Errorf("TestStridedSequential: got %v (error %v), expected %v", v, err, ErrExhausted)
+	}
+	gen, _ = NewStridedSequential(math.MinInt64, 1)
+	if v, err := gen.NewIDs(math.MaxInt64); err != nil || v != -2 {
+		t.Errorf("TestStridedSequential: got %v (error %v), expected -2", v, err)
+	}
+}
+
+func TestNegSequentialRange(t *testing.T) {
+	t.Parallel()
+	if _, err := NewNegSequentialRange(-1, -2, false); err == nil {
+		t.Error("TestNegSequentialRange: expected error for floor > ceiling")
+	}
+	up, _ := NewNegSequentialRange(-20, -11, false)
+	down, _ := NewNegSequentialRange(-20, -11, true)
+	full, _ := NewNegSequentialRange(math.MinInt64, math.MaxInt64, true)
+	var tests = []struct {
+		gen             Interface
+		count, expected int64
+		err             error
+	}{
+		{up, 1, -20, nil},
+		{up, 8, -12, nil},
+		{up, 2, 0, ErrExhausted},
+		{up, 1, -11, nil},
+		{up, 1, 0, ErrExhausted},
+		{down, 1, -11, nil},
+		{down, 3, -14, nil},
+		{down, 7, 0, ErrExhausted},
+		{down, 6, -20, nil},
+		{down, 1, 0, ErrExhausted},
+		{full, 1, math.MaxInt64, nil},
+		{full, math.MaxInt64 - 1, 1, nil},
+		{full, math.MaxInt64, math.MinInt64 + 2, nil},
+		{full, 1, math.MinInt64 + 1, nil},
+		{full, 1, 0, ErrExhausted},
+	}
+	for i, test := range tests {
+		v, err := test.gen.NewIDs(test.count)
+		if v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("TestNegSequentialRange %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+
+	down, _ = NewNegSequentialRange(-20, -11, true)
+	r, err := NewIDRange(down, 3)
+	if err != nil || r.First() != -11 || r.Last() != -13 || r.Step() != -1 {
+		t.Errorf("TestNegSequentialRange: got %+v (error %v)", r, err)
+	}
+}
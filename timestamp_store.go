@@ -0,0 +1,60 @@
+package idgen
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// TimestampStore persists the highest timestamp a Snowflake-like generator may have
+// used, so that a restarted process does not reuse timestamps after the clock was set
+// back. Values are opaque to the store and only meaningful to generators sharing the
+// same layout and options.
+type TimestampStore interface {
+	// Load returns the persisted timestamp, or 0 if none was saved.
+	Load() (int64, error)
+	// Save durably records ts, replacing the previous value.
+	Save(ts int64) error
+}
+
+type fileTimestampStore string
+
+// NewFileTimestampStore returns a TimestampStore that keeps the timestamp in the file at
+// path. Saves write a temporary file in the same directory and rename it over path, so
+// a crash never leaves a truncated value behind.
+func NewFileTimestampStore(path string) TimestampStore {
+	return fileTimestampStore(path)
+}
+
+func (f fileTimestampStore) Load() (int64, error) {
+	b, err := os.ReadFile(string(f))
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+func (f fileTimestampStore) Save(ts int64) error {
+	path := string(f)
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.WriteString(strconv.FormatInt(ts, 10) + "\n")
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,92 @@
+package idgen
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// memTimestampStore is a TimestampStore that counts saves.
+type memTimestampStore struct {
+	ts    int64
+	saves int
+	err   error
+}
+
+func (m *memTimestampStore) Load() (int64, error) { return m.ts, m.err }
+
+func (m *memTimestampStore) Save(ts int64) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.ts = ts
+	m.saves++
+	return nil
+}
+
+func TestSnowflakeTimestampStore(t *testing.T) {
+	t.Parallel()
+	store := &memTimestampStore{}
+	now := int64(time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	opts := []Option{WithClock(clock), WithTimestampStore(store, 10*time.Millisecond)}
+
+	gen := NewSnowflake(3, opts...)
+	var tests = []struct {
+		advance, expected int64
+		saves             int
+	}{
+		{0, 1<<22 | 3<<12, 1},
+		{int64(5 * time.Millisecond), 6<<22 | 3<<12, 1},
+		{int64(5 * time.Millisecond), 11<<22 | 3<<12, 2},
+	}
+	for i, test := range tests {
+		now += test.advance
+		v, err := gen.NewIDs(1)
+		if err != nil || v != test.expected || store.saves != test.saves {
+			t.Errorf("TestSnowflakeTimestampStore %d: got %v (error %v, %d saves), expected %v (%d saves)",
+				i, v, err, store.saves, test.expected, test.saves)
+		}
+	}
+	if store.ts != 21<<22 {
+		t.Errorf("TestSnowflakeTimestampStore: got stored %v, expected %v", store.ts, 21<<22)
+	}
+
+	// Restart after the clock was set back: everything up to the horizon is refused.
+	now = int64(2 * time.Millisecond)
+	gen = NewSnowflake(3, opts...)
+	for _, ms := range []int64{2, 11, 21} {
+		now = ms * int64(time.Millisecond)
+		if v, err := gen.NewIDs(1); err != ErrClockMovedBack {
+			t.Errorf("TestSnowflakeTimestampStore at %dms: got %v (error %v), expected %v",
+				ms, v, err, ErrClockMovedBack)
+		}
+	}
+	now = 22 * int64(time.Millisecond)
+	if v, err := gen.NewIDs(1); err != nil || v != 22<<22|3<<12 {
+		t.Errorf("TestSnowflakeTimestampStore: got %v (error %v), expected %v",
+			v, err, 22<<22|3<<12)
+	}
+
+	store.err = errTest
+	gen = NewSnowflake(3, opts...)
+	if _, err := gen.NewIDs(1); err != errTest {
+		t.Errorf("TestSnowflakeTimestampStore: got error %v, expected %v", err, errTest)
+	}
+}
+
+func TestFileTimestampStore(t *testing.T) {
+	t.Parallel()
+	store := NewFileTimestampStore(filepath.Join(t.TempDir(), "last"))
+	if v, err := store.Load(); err != nil || v != 0 {
+		t.Errorf("TestFileTimestampStore: got %v (error %v), expected 0", v, err)
+	}
+	for _, ts := range []int64{42, 1 << 62} {
+		if err := store.Save(ts); err != nil {
+			t.Fatalf("TestFileTimestampStore: got error %v", err)
+		}
+		if v, err := store.Load(); err != nil || v != ts {
+			t.Errorf("TestFileTimestampStore: got %v (error %v), expected %v", v, err, ts)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TwitterEpoch is the start of the timestamps of IDs minted by Twitter's Snowflake
+// service (2010-11-04 01:42:54.657 UTC). This package's NewSnowflake uses the Unix epoch
+// instead, so the two are not interchangeable.
+var TwitterEpoch = time.UnixMilli(1288834974657).UTC()
+
+// TwitterSnowflake holds the fields of an ID minted by Twitter's Snowflake service.
+type TwitterSnowflake struct {
+	ID   int64
+	Time time.Time
+	// Worker is the 10-bit machine ID: Datacenter in its 5 high bits, WorkerInDatacenter
+	// in its 5 low bits.
+	Worker                         int64
+	Datacenter, WorkerInDatacenter int64
+	Sequence                       int64
+}
+
+// ParseTwitterSnowflake parses a Twitter ID (e.g. a tweet's id_str), returning its
+// creation time and worker. IDs from before Snowflake was deployed (late 2010) are
+// sequential and have no meaningful fields.
+func ParseTwitterSnowflake(s string) (TwitterSnowflake, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id < 0 {
+		return TwitterSnowflake{}, fmt.Errorf("ParseTwitterSnowflake(%q): invalid ID", s)
+	}
+	return DecomposeTwitterSnowflake(id), nil
+}
+
+// DecomposeTwitterSnowflake splits a Twitter ID into its fields, with Time in UTC.
+func DecomposeTwitterSnowflake(id int64) TwitterSnowflake {
+	ts, node, seq := SnowflakeLayout.Decompose(id, WithEpoch(TwitterEpoch))
+	return TwitterSnowflake{
+		ID:                 id,
+		Time:               ts.UTC(),
+		Worker:             node,
+		Datacenter:         node >> 5,
+		WorkerInDatacenter: node & (1<<5 - 1),
+		Sequence:           seq,
+	}
+}
@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTwitterSnowflake(t *testing.T) {
+	t.Parallel()
+	tw, err := ParseTwitterSnowflake("1212092628029698048")
+	expected := TwitterSnowflake{
+		ID:                 1212092628029698048,
+		Time:               time.Date(2019, 12, 31, 19, 26, 16, 771e6, time.UTC),
+		Worker:             327,
+		Datacenter:         10,
+		WorkerInDatacenter: 7,
+		Sequence:           0,
+	}
+	if err != nil || tw != expected {
+		t.Errorf("TestParseTwitterSnowflake: got %+v (error %v), expected %+v", tw, err, expected)
+	}
+	if tw, err := ParseTwitterSnowflake("1e18"); err == nil {
+		t.Errorf("TestParseTwitterSnowflake: got %+v, expected error", tw)
+	}
+}
@@ -0,0 +1,35 @@
+package idgen
+
+// Gen generates IDs of a distinct type, e.g. UserID or OrderID, so the compiler rejects
+// passing one kind of ID where another is expected. The zero value is not usable; see
+// Typed.
+type Gen[T ~int64] struct {
+	gen Interface
+}
+
+// Typed returns a Gen minting IDs of type T with gen:
+//
+//	type UserID int64
+//	users := idgen.Typed[UserID](idgen.NewSnowflake(1))
+//	id, err := users.New() // id is a UserID.
+//
+// Safe for concurrent use if gen is.
+func Typed[T ~int64](gen Interface) Gen[T] {
+	return Gen[T]{gen}
+}
+
+// NewIDs generates n IDs like Interface.NewIDs, returning the last one.
+func (g Gen[T]) NewIDs(n int64) (T, error) {
+	v, err := g.gen.NewIDs(n)
+	return T(v), err
+}
+
+// New generates one ID.
+func (g Gen[T]) New() (T, error) {
+	return g.NewIDs(1)
+}
+
+// Untyped returns the underlying generator, e.g. to wrap it.
+func (g Gen[T]) Untyped() Interface {
+	return g.gen
+}
@@ -0,0 +1,31 @@
+package idgen
+
+import "testing"
+
+type testUserID int64
+
+func TestTyped(t *testing.T) {
+	t.Parallel()
+	users := Typed[testUserID](NewSequential())
+	var tests = []struct {
+		count    int64
+		expected testUserID
+	}{
+		{1, 1},
+		{3, 4},
+	}
+	for i, test := range tests {
+		if v, err := users.NewIDs(test.count); err != nil || v != test.expected {
+			t.Errorf("TestTyped %d: got %v (error %v), expected %v", i, v, err, test.expected)
+		}
+	}
+	if v, err := users.New(); err != nil || v != 5 {
+		t.Errorf("TestTyped: got %v (error %v), expected 5", v, err)
+	}
+	if v, err := users.Untyped().NewIDs(1); err != nil || v != 6 {
+		t.Errorf("TestTyped: got %v (error %v), expected 6", v, err)
+	}
+	if _, err := Typed[testUserID](broken{errTest}).New(); err != errTest {
+		t.Errorf("TestTyped: got error %v, expected %v", err, errTest)
+	}
+}
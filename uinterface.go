@@ -0,0 +1,71 @@
+package idgen
+
+import "fmt"
+
+// UInterface is like Interface, for generators using all 64 bits. Unsigned IDs are
+// useful where storage is unsigned (e.g. MySQL BIGINT UNSIGNED) or opaque; int64 columns
+// (e.g. PostgreSQL bigint) hold them converted with int64(id), but then IDs with bit 63
+// set are negative and sort before the others. Use Signed and Unsigned to convert
+// generators, checking the range.
+type UInterface interface {
+	NewIDs(n int64) (uint64, error)
+}
+
+type unsigned struct {
+	gen  Interface
+	name string
+}
+
+// Unsigned adapts gen to UInterface. NewIDs fails with ErrOverflow at bit 63 for
+// negative IDs, which would sort after all others.
+func Unsigned(gen Interface) UInterface {
+	return unsigned{gen: gen, name: fmt.Sprintf("%T", gen)}
+}
+
+func (u unsigned) NewIDs(n int64) (uint64, error) {
+	v, err := u.gen.NewIDs(n)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, &Error{Generator: u.name, Count: n, Value: v, Bit: 63, Err: ErrOverflow}
+	}
+	return uint64(v), nil
+}
+
+type signed struct {
+	gen  UInterface
+	name string
+}
+
+// Signed adapts gen to Interface. NewIDs fails with ErrOverflow at bit 63 for IDs above
+// the largest int64, so the signed API never sees negative IDs.
+func Signed(gen UInterface) Interface {
+	return signed{gen: gen, name: fmt.Sprintf("%T", gen)}
+}
+
+func (s signed) NewIDs(n int64) (int64, error) {
+	v, err := s.gen.NewIDs(n)
+	if err != nil {
+		return 0, err
+	}
+	if int64(v) < 0 {
+		return 0, &Error{Generator: s.name, Count: n, Value: int64(v), Bit: 63, Err: ErrOverflow}
+	}
+	return int64(v), nil
+}
+
+type unsignedSnowflake struct {
+	gen Interface
+}
+
+// NewUnsignedSnowflake is like NewSnowflake, for layouts of up to 64 bits (e.g. 42 time
+// bits, doubling the lifetime of SnowflakeLayout). See ValidateUnsigned.
+func (l Layout) NewUnsignedSnowflake(nodeMask int64, opts ...Option) UInterface {
+	return unsignedSnowflake{l.NewSnowflake(nodeMask, opts...)}
+}
+
+func (u unsignedSnowflake) NewIDs(n int64) (uint64, error) {
+	v, err := u.gen.NewIDs(n)
+	return uint64(v), err
+}
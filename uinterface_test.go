@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestUnsignedSnowflake(t *testing.T) {
+	t.Parallel()
+	l := Layout{TimeBits: 42, NodeBits: 10, SeqBits: 12}
+	if err := l.Validate(1); err == nil {
+		t.Errorf("TestUnsignedSnowflake: expected Validate error for 64 bits")
+	}
+	// Past 2^41 Milliseconds (year 2039), the timestamp reaches bit 63.
+	now := int64(1<<41-1) * int64(time.Millisecond)
+	clock := ClockFunc(func() int64 { return now })
+	if err := l.ValidateUnsigned(1, WithClock(clock)); err != nil {
+		t.Errorf("TestUnsignedSnowflake: got error %v", err)
+	}
+	gen := l.NewUnsignedSnowflake(1, WithClock(clock))
+	var tests = []struct {
+		advance  time.Duration
+		expected uint64
+		err      error
+	}{
+		{0, (1<<41-1)<<22 | 1<<12, nil},
+		{time.Millisecond, 1<<63 | 1<<12, nil},
+		{time.Millisecond, 1<<63 | 1<<22 | 1<<12, nil},
+		{-time.Millisecond, 0, ErrClockMovedBack},
+	}
+	for i, test := range tests {
+		now += int64(test.advance)
+		if v, err := gen.NewIDs(1); v != test.expected || !errors.Is(err, test.err) {
+			t.Errorf("TestUnsignedSnowflake %d: got %v (error %v), expected %v (error %v)",
+				i, v, err, test.expected, test.err)
+		}
+	}
+	id := uint64(1<<63 | 1<<22 | 1<<12 | 5)
+	ts, node, seq := l.Decompose(int64(id))
+	if ts.UnixMilli() != 1<<41+1 || node != 1 || seq != 5 {
+		t.Errorf("TestUnsignedSnowflake: got %v, %v, %v", ts.UnixMilli(), node, seq)
+	}
+}
+
+func TestSignedUnsigned(t *testing.T) {
+	t.Parallel()
+	if v, err := Unsigned(NewConstant(5)).NewIDs(1); err != nil || v != 5 {
+		t.Errorf("TestSignedUnsigned: got %v (error %v), expected 5", v, err)
+	}
+	if v, err := Unsigned(NewNegSequential()).NewIDs(1); !errors.Is(err, ErrOverflow) {
+		t.Errorf("TestSignedUnsigned: got %v (error %v), expected %v", v, err, ErrOverflow)
+	}
+	if v, err := Signed(Unsigned(NewSequential())).NewIDs(3); err != nil || v != 3 {
+		t.Errorf("TestSignedUnsigned: got %v (error %v), expected 3", v, err)
+	}
+	gen := Layout{TimeBits: 1, SeqBits: 63}.NewUnsignedSnowflake(0,
+		WithClock(ClockFunc(func() int64 { return int64(time.Millisecond) })))
+	var e *Error
+	if v, err := Signed(gen).NewIDs(1); !errors.As(err, &e) || e.Bit != 63 {
+		t.Errorf("TestSignedUnsigned: got %v (error %v), expected overflow at bit 63", v, err)
+	}
+	if _, err := Unsigned(broken{errTest}).NewIDs(1); err != errTest {
+		t.Errorf("TestSignedUnsigned: got error %v, expected %v", err, errTest)
+	}
+}
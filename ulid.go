@@ -0,0 +1,92 @@
+package idgen
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier: 48 bits of
+// timestamp (Milliseconds since Unix epoch) followed by 80 random bits, big-endian.
+type ULID [16]byte
+
+// NewULID produces a ULID with the current time and randomness read from entropy.
+func NewULID(entropy io.Reader) (ULID, error) {
+	var ulid ULID
+	if _, err := io.ReadFull(entropy, ulid[6:]); err != nil {
+		return ulid, err
+	}
+	ulid.setTime(time.Now().UnixNano() / int64(time.Millisecond))
+	return ulid, nil
+}
+
+// MonotonicULID generates ULIDs that strictly increase, even when created within the
+// same Millisecond: in that case the random component of the previous ULID is incremented
+// instead of reading new entropy. Safe for concurrent use.
+type MonotonicULID struct {
+	mu      sync.Mutex
+	entropy io.Reader
+	last    ULID
+}
+
+// NewMonotonicULID returns a MonotonicULID reading randomness from entropy.
+func NewMonotonicULID(entropy io.Reader) *MonotonicULID {
+	return &MonotonicULID{entropy: entropy}
+}
+
+// New returns the next ULID. An error is returned if the random component overflows
+// within a Millisecond (2^80 increments).
+func (m *MonotonicULID) New() (ULID, error) {
+	return m.next(time.Now().UnixNano() / int64(time.Millisecond))
+}
+
+func (m *MonotonicULID) next(ms int64) (ULID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if ms <= m.last.Time() {
+		// Same Millisecond (or clock went back): keep the ordering.
+		ulid := m.last
+		for i := len(ulid) - 1; i >= 6; i-- {
+			if ulid[i]++; ulid[i] != 0 {
+				m.last = ulid
+				return ulid, nil
+			}
+		}
+		return ULID{}, fmt.Errorf("MonotonicULID.New(): %w", ErrOverflow)
+	}
+	var ulid ULID
+	if _, err := io.ReadFull(m.entropy, ulid[6:]); err != nil {
+		return ulid, err
+	}
+	ulid.setTime(ms)
+	m.last = ulid
+	return ulid, nil
+}
+
+func (ulid *ULID) setTime(ms int64) {
+	for i := 5; i >= 0; i-- {
+		ulid[i] = byte(ms)
+		ms >>= 8
+	}
+}
+
+// Time returns the ULID timestamp (Milliseconds since Unix epoch).
+func (ulid ULID) Time() int64 {
+	var ms int64
+	for _, b := range ulid[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	return ms
+}
+
+// ParseULID decodes the representation produced by ULID.String. Decoding is
+// case-insensitive and follows Crockford's normalization rules.
+func ParseULID(s string) (ULID, error) {
+	return DecodeCrockford128(s)
+}
+
+// String returns ULID in canonical format (26 Crockford Base32 characters).
+func (ulid ULID) String() string {
+	return EncodeCrockford128(ulid)
+}
@@ -0,0 +1,86 @@
+package idgen
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestULID(t *testing.T) {
+	ids := []struct {
+		v ULID
+		s string
+	}{
+		{ULID{}, "00000000000000000000000000"},
+		{[...]byte{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x8c, 0x5b, 0x2e, 0x0d, 0xd7, 0x3f, 0x1c,
+			0x84, 0x3c, 0x91, 0x5f}, "01ARYZ6S4CBCQ0VNSZ3J23S4AZ"},
+		{[...]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			0xff, 0xff, 0xff, 0xff}, "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+	}
+	for i, id := range ids {
+		if s := id.v.String(); s != id.s {
+			t.Errorf("%d: repr, got %s, expected %s", i, s, id.s)
+		}
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	random := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	ulid, err := NewULID(bytes.NewReader(random))
+	expected := time.Now().UnixNano() / int64(time.Millisecond)
+	switch {
+	case err != nil:
+		t.Errorf("got error %q", err)
+	case math.Abs(float64(ulid.Time()-expected)) > 1.:
+		t.Errorf("time, got %v, expected %v", ulid.Time(), expected)
+	case !bytes.Equal(ulid[6:], random):
+		t.Errorf("entropy, got %v, expected %v", ulid[6:], random)
+	}
+	if _, err := NewULID(bytes.NewReader(random[:9])); err == nil {
+		t.Errorf("short entropy: expected error")
+	}
+}
+
+func TestMonotonicULID(t *testing.T) {
+	random := make([]byte, 30)
+	random[18], random[19] = 0xff, 0xff
+	copy(random[20:], bytes.Repeat([]byte{0xff}, 10))
+	m := NewMonotonicULID(bytes.NewReader(random))
+	ids := []struct {
+		ms          int64
+		expectError bool
+		expected    string
+	}{
+		{1, false, "00000000010000000000000000"},
+		{1, false, "00000000010000000000000001"},
+		{0, false, "00000000010000000000000002"}, // clock went back
+		{2, false, "00000000020000000000001ZZZ"},
+		{2, false, "00000000020000000000002000"},
+		{3, false, "0000000003ZZZZZZZZZZZZZZZZ"},
+		{3, true, ""},
+		{4, true, ""}, // entropy exhausted
+	}
+	for i, id := range ids {
+		ulid, err := m.next(id.ms)
+		switch {
+		case id.expectError && err == nil:
+			t.Errorf("%d: expected error, got %v", i, ulid)
+		case !id.expectError && err != nil:
+			t.Errorf("%d: got error %q", i, err)
+		case !id.expectError && ulid.String() != id.expected:
+			t.Errorf("%d: got %v, expected %v", i, ulid, id.expected)
+		}
+	}
+
+	m = NewMonotonicULID(rand.New(rand.NewSource(1)))
+	var prev ULID
+	for i := 0; i < 1000; i++ {
+		ulid, err := m.New()
+		if err != nil || ulid.String() <= prev.String() {
+			t.Fatalf("%d: got %v (error %v) after %v", i, ulid, err, prev)
+		}
+		prev = ulid
+	}
+}
@@ -0,0 +1,34 @@
+package idgen
+
+// ULIDs and UUIDs are both 16 bytes, so they convert losslessly. The result of ULIDToUUID
+// is usually not a valid RFC 9562 UUID (version and variant bits are random), but
+// databases store it fine, and it sorts like the ULID in binary columns.
+
+// ULIDToUUID returns the UUID with the same bytes as ulid.
+func ULIDToUUID(ulid ULID) UUID {
+	return UUID(ulid)
+}
+
+// UUIDToULID returns the ULID with the same bytes as uuid. For version 7 UUIDs, the
+// ULID timestamp is the UUID's.
+func UUIDToULID(uuid UUID) ULID {
+	return ULID(uuid)
+}
+
+// ULIDStringToUUID re-encodes a ULID string in UUID canonical format.
+func ULIDStringToUUID(s string) (string, error) {
+	ulid, err := ParseULID(s)
+	if err != nil {
+		return "", err
+	}
+	return ULIDToUUID(ulid).String(), nil
+}
+
+// UUIDStringToULID re-encodes a UUID string in ULID canonical format.
+func UUIDStringToULID(s string) (string, error) {
+	uuid, err := ParseUUID(s)
+	if err != nil {
+		return "", err
+	}
+	return UUIDToULID(uuid).String(), nil
+}
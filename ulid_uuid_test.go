@@ -0,0 +1,35 @@
+package idgen
+
+import "testing"
+
+func TestULIDUUID(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		ulid, uuid string
+	}{
+		{"00000000000000000000000000", "00000000-0000-0000-0000-000000000000"},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01563e3a-b5d3-d676-4c61-efb99302bd5b"},
+		{"7ZZZZZZZZZZZZZZZZZZZZZZZZZ", "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+	}
+	for i, test := range tests {
+		if s, err := ULIDStringToUUID(test.ulid); err != nil || s != test.uuid {
+			t.Errorf("TestULIDUUID %d: got %q (error %v), expected %q", i, s, err, test.uuid)
+		}
+		if s, err := UUIDStringToULID(test.uuid); err != nil || s != test.ulid {
+			t.Errorf("TestULIDUUID %d: got %q (error %v), expected %q", i, s, err, test.ulid)
+		}
+	}
+	if _, err := ULIDStringToUUID("8ZZZZZZZZZZZZZZZZZZZZZZZZZ"); err == nil {
+		t.Errorf("TestULIDUUID: expected error for an overflowing ULID")
+	}
+	if _, err := UUIDStringToULID("not-a-uuid"); err == nil {
+		t.Errorf("TestULIDUUID: expected error for an invalid UUID")
+	}
+
+	uuid, _ := NewUUIDv7()
+	ulid := UUIDToULID(uuid)
+	ts, _ := uuid.Time()
+	if ulid.Time() != ts.UnixMilli() || ULIDToUUID(ulid) != uuid {
+		t.Errorf("TestULIDUUID: got %v (time %v) from %v (time %v)", ulid, ulid.Time(), uuid, ts)
+	}
+}
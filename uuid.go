@@ -1,29 +1,299 @@
 package idgen
 
 import (
+	"crypto/md5"
+	crand "crypto/rand"
+	"crypto/sha1"
 	"fmt"
+	"hash"
+	"io"
 	"math/rand"
+	"net"
+	"sync"
+	"time"
 )
 
 // UUID is defined by RFC 4122
 type UUID [16]byte
 
-// NewUUIDv4 produces random (version 4) UUID.
-func NewUUIDv4(r *rand.Rand) (UUID, error) {
+// gregorianOffset is the number of 100-Nanosecond intervals between the start of the
+// Gregorian calendar (1582-10-15), used by time-based UUIDs, and the Unix epoch.
+const gregorianOffset = 122192928000000000
+
+// uuidClock holds the state shared by time-based (version 1) UUIDs. It's safe for
+// concurrent use.
+type uuidClock struct {
+	sync.Mutex
+	lastTime uint64
+	clockSeq uint16
+	node     [6]byte
+	init     bool
+}
+
+var defaultUUIDClock uuidClock
+
+// NilUUID has all bits set to zero, MaxUUID has all bits set to one (RFC 9562 section 5.10
+// and 5.11).
+var (
+	NilUUID = UUID{}
+	MaxUUID = UUID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+)
+
+// Name space IDs for name-based UUIDs, see RFC 4122 appendix C.
+var (
+	NamespaceDNS  = UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL  = UUID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceOID  = UUID{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceX500 = UUID{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// NewUUIDv1 produces time-based (version 1) UUID: a 60-bit timestamp (100-Nanosecond
+// intervals since 1582-10-15), a 14-bit clock sequence and the 48-bit node. The node is
+// the MAC address of the first network interface that has one, or a random value (with
+// the multicast bit set, see section 4.5) if none is found.
+// Safe for concurrent use.
+func NewUUIDv1() (UUID, error) {
+	return defaultUUIDClock.newUUIDv1(gregorianNow())
+}
+
+func gregorianNow() uint64 {
+	return uint64(time.Now().UnixNano()/100) + gregorianOffset
+}
+
+// next returns the timestamp, clock sequence and node for a new UUID. The clock sequence
+// is incremented when now is not after the previous timestamp, so values never repeat.
+func (c *uuidClock) next(now uint64) (uint64, uint16, [6]byte, error) {
+	c.Lock()
+	defer c.Unlock()
+	if !c.init {
+		var seq [2]byte
+		if _, err := io.ReadFull(crand.Reader, seq[:]); err != nil {
+			return 0, 0, c.node, err
+		}
+		c.clockSeq = (uint16(seq[0])<<8 | uint16(seq[1])) & 0x3fff
+		if !macAddress(c.node[:]) {
+			if _, err := io.ReadFull(crand.Reader, c.node[:]); err != nil {
+				return 0, 0, c.node, err
+			}
+			c.node[0] |= 0x01
+		}
+		c.init = true
+	}
+	if now <= c.lastTime {
+		c.clockSeq = (c.clockSeq + 1) & 0x3fff
+	}
+	c.lastTime = now
+	return now, c.clockSeq, c.node, nil
+}
+
+func (c *uuidClock) newUUIDv1(now uint64) (UUID, error) {
+	var uuid UUID
+	ts, seq, node, err := c.next(now)
+	if err != nil {
+		return uuid, err
+	}
+	// time_low, time_mid, time_hi_and_version, see section 4.1.2.
+	uuid[0], uuid[1], uuid[2], uuid[3] = byte(ts>>24), byte(ts>>16), byte(ts>>8), byte(ts)
+	uuid[4], uuid[5] = byte(ts>>40), byte(ts>>32)
+	uuid[6], uuid[7] = byte(ts>>56)&0x0f|(1<<4), byte(ts>>48)
+	// variant, section 4.1.1:
+	// 10xx xxxx (0x8/9/a/b)
+	uuid[8], uuid[9] = byte(seq>>8)&0x3f|0x80, byte(seq)
+	copy(uuid[10:], node[:])
+	return uuid, nil
+}
+
+// NewUUIDv6 produces reordered time-based (version 6) UUID, as defined by RFC 9562: the
+// same fields as version 1, but with the timestamp stored most significant bits first so
+// UUIDs sort by creation time. It shares the clock sequence and node with NewUUIDv1.
+// Safe for concurrent use.
+func NewUUIDv6() (UUID, error) {
+	return defaultUUIDClock.newUUIDv6(gregorianNow())
+}
+
+func (c *uuidClock) newUUIDv6(now uint64) (UUID, error) {
 	var uuid UUID
-	_, err := r.Read(uuid[:16])
+	ts, seq, node, err := c.next(now)
+	if err != nil {
+		return uuid, err
+	}
+	// time_high, time_mid, time_low_and_version, see RFC 9562 section 5.6.
+	uuid[0], uuid[1], uuid[2], uuid[3] = byte(ts>>52), byte(ts>>44), byte(ts>>36), byte(ts>>28)
+	uuid[4], uuid[5] = byte(ts>>20), byte(ts>>12)
+	uuid[6], uuid[7] = byte(ts>>8)&0x0f|(6<<4), byte(ts)
+	uuid[8], uuid[9] = byte(seq>>8)&0x3f|0x80, byte(seq)
+	copy(uuid[10:], node[:])
+	return uuid, nil
+}
+
+// macAddress copies the first available hardware address into node.
+func macAddress(node []byte) bool {
+	ifaces, err := net.Interfaces()
 	if err != nil {
+		return false
+	}
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) >= len(node) {
+			copy(node, iface.HardwareAddr)
+			return true
+		}
+	}
+	return false
+}
+
+// NewUUIDv4 produces random (version 4) UUID. A seeded r gives reproducible results, so
+// it is suitable for tests; production code should prefer NewUUIDv4Crypto.
+func NewUUIDv4(r *rand.Rand) (UUID, error) {
+	return NewUUIDv4Reader(r)
+}
+
+// NewUUIDv4Crypto produces random (version 4) UUID using crypto/rand.
+func NewUUIDv4Crypto() (UUID, error) {
+	return NewUUIDv4Reader(crand.Reader)
+}
+
+// NewUUIDv4Reader produces random (version 4) UUID reading randomness from r.
+func NewUUIDv4Reader(r io.Reader) (UUID, error) {
+	var uuid UUID
+	if _, err := io.ReadFull(r, uuid[:]); err != nil {
 		return uuid, err
 	}
+	uuid.setV4()
+	return uuid, nil
+}
+
+// NewUUIDs produces n random (version 4) UUIDs, filling all of them with a single read
+// from r.
+func NewUUIDs(r io.Reader, n int) ([]UUID, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("NewUUIDs() supports count>=0, got %v", n)
+	}
+	buf := make([]byte, n*len(UUID{}))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	uuids := make([]UUID, n)
+	for i := range uuids {
+		copy(uuids[i][:], buf[i*len(UUID{}):])
+		uuids[i].setV4()
+	}
+	return uuids, nil
+}
+
+func (uuid *UUID) setV4() {
 	// variant, section 4.1.1:
 	// 10xx xxxx (0x8/9/a/b)
 	uuid[8] = uuid[8]&0x3f | 0x80
 	// version 4, see section 4.1.3:
 	// 0100 xxxx (0x4)
 	uuid[6] = uuid[6]&0x0f | (4 << 4)
+}
+
+// NewUUIDv3 produces name-based (version 3) UUID, hashing namespace and name with MD5.
+// The same inputs always produce the same UUID.
+func NewUUIDv3(namespace UUID, name []byte) UUID {
+	return newHashUUID(md5.New(), 3, namespace, name)
+}
+
+// NewUUIDv5 produces name-based (version 5) UUID, hashing namespace and name with SHA-1.
+// The same inputs always produce the same UUID.
+func NewUUIDv5(namespace UUID, name []byte) UUID {
+	return newHashUUID(sha1.New(), 5, namespace, name)
+}
+
+// newHashUUID implements section 4.3.
+func newHashUUID(h hash.Hash, version byte, namespace UUID, name []byte) UUID {
+	var uuid UUID
+	h.Write(namespace[:])
+	h.Write(name)
+	copy(uuid[:], h.Sum(nil))
+	uuid[8] = uuid[8]&0x3f | 0x80
+	uuid[6] = uuid[6]&0x0f | (version << 4)
+	return uuid
+}
+
+// NewUUIDv7 produces time-ordered (version 7) UUID, as defined by RFC 9562: a 48-bit
+// Unix timestamp (Millisecond precision) followed by random bits from crypto/rand.
+func NewUUIDv7() (UUID, error) {
+	return newUUIDv7(crand.Reader, time.Now().UnixNano()/int64(time.Millisecond))
+}
+
+func newUUIDv7(r io.Reader, ms int64) (UUID, error) {
+	var uuid UUID
+	if _, err := io.ReadFull(r, uuid[6:]); err != nil {
+		return uuid, err
+	}
+	for i := 5; i >= 0; i-- {
+		uuid[i] = byte(ms)
+		ms >>= 8
+	}
+	// variant, RFC 9562 section 4.1:
+	// 10xx xxxx (0x8/9/a/b)
+	uuid[8] = uuid[8]&0x3f | 0x80
+	// version 7, see section 4.2:
+	// 0111 xxxx (0x7)
+	uuid[6] = uuid[6]&0x0f | (7 << 4)
+	return uuid, nil
+}
+
+// ParseUUID decodes a UUID in canonical format (as returned by UUID.String). Both lower
+// and upper case hex digits are accepted.
+func ParseUUID(s string) (UUID, error) {
+	var uuid UUID
+	if len(s) != 36 {
+		return uuid, fmt.Errorf("ParseUUID(%q): expected length 36, got %d", s, len(s))
+	}
+	j := 0
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return uuid, fmt.Errorf("ParseUUID(%q): expected '-' at %d, got %q", s, i, s[i])
+			}
+			continue
+		}
+		v := hexDigit(s[i])
+		if v < 0 {
+			return uuid, fmt.Errorf("ParseUUID(%q): invalid hex digit %q at %d", s, s[i], i)
+		}
+		uuid[j/2] |= byte(v) << (4 * uint(1-j%2))
+		j++
+	}
+	return uuid, nil
+}
+
+// MustParseUUID is like ParseUUID but panics if s is invalid. It simplifies the
+// initialization of global variables.
+func MustParseUUID(s string) UUID {
+	uuid, err := ParseUUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return uuid
+}
+
+// UUIDFromBytes copies a 16-byte binary representation into a UUID.
+func UUIDFromBytes(b []byte) (UUID, error) {
+	var uuid UUID
+	if len(b) != len(uuid) {
+		return uuid, fmt.Errorf("UUIDFromBytes: expected length %d, got %d", len(uuid), len(b))
+	}
+	copy(uuid[:], b)
 	return uuid, nil
 }
 
+func hexDigit(c byte) int {
+	switch {
+	case '0' <= c && c <= '9':
+		return int(c - '0')
+	case 'a' <= c && c <= 'f':
+		return int(c-'a') + 10
+	case 'A' <= c && c <= 'F':
+		return int(c-'A') + 10
+	}
+	return -1
+}
+
 // String returns UUID in cannonical format.
 func (uuid UUID) String() string {
 	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:])
@@ -0,0 +1,70 @@
+package idgen
+
+import (
+	"fmt"
+	"time"
+)
+
+// Variant is the layout of a UUID, see RFC 4122 section 4.1.1.
+type Variant byte
+
+// UUID variants.
+const (
+	VariantNCS Variant = iota
+	VariantRFC4122
+	VariantMicrosoft
+	VariantFuture
+)
+
+// Version returns the UUID version (the meaning only applies to VariantRFC4122).
+func (uuid UUID) Version() int {
+	return int(uuid[6] >> 4)
+}
+
+// Variant returns the UUID variant.
+func (uuid UUID) Variant() Variant {
+	switch {
+	case uuid[8]&0x80 == 0x00:
+		return VariantNCS
+	case uuid[8]&0xc0 == 0x80:
+		return VariantRFC4122
+	case uuid[8]&0xe0 == 0xc0:
+		return VariantMicrosoft
+	}
+	return VariantFuture
+}
+
+// IsValid reports whether UUID has VariantRFC4122 and a known version (1 to 8).
+// NilUUID and MaxUUID are not valid.
+func (uuid UUID) IsValid() bool {
+	v := uuid.Version()
+	return uuid.Variant() == VariantRFC4122 && v >= 1 && v <= 8
+}
+
+// Time returns the timestamp embedded in time-based UUIDs (versions 1, 6 and 7).
+func (uuid UUID) Time() (time.Time, error) {
+	if uuid.Variant() != VariantRFC4122 {
+		return time.Time{}, fmt.Errorf("%v.Time(): unsupported variant %d", uuid, uuid.Variant())
+	}
+	var ts uint64
+	switch uuid.Version() {
+	case 1:
+		ts = uint64(uuid[6]&0x0f)<<56 | uint64(uuid[7])<<48 | uint64(uuid[4])<<40 |
+			uint64(uuid[5])<<32 | uint64(uuid[0])<<24 | uint64(uuid[1])<<16 |
+			uint64(uuid[2])<<8 | uint64(uuid[3])
+	case 6:
+		ts = uint64(uuid[0])<<52 | uint64(uuid[1])<<44 | uint64(uuid[2])<<36 |
+			uint64(uuid[3])<<28 | uint64(uuid[4])<<20 | uint64(uuid[5])<<12 |
+			uint64(uuid[6]&0x0f)<<8 | uint64(uuid[7])
+	case 7:
+		var ms int64
+		for _, b := range uuid[:6] {
+			ms = ms<<8 | int64(b)
+		}
+		return time.Unix(ms/1000, ms%1000*int64(time.Millisecond)), nil
+	default:
+		return time.Time{}, fmt.Errorf("%v.Time(): unsupported version %d", uuid, uuid.Version())
+	}
+	ns := int64(ts-gregorianOffset) * 100
+	return time.Unix(ns/int64(time.Second), ns%int64(time.Second)), nil
+}
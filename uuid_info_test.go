@@ -0,0 +1,60 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUUIDVersionVariant(t *testing.T) {
+	tests := []struct {
+		s       string
+		version int
+		variant Variant
+		valid   bool
+	}{
+		{"00000000-0000-0000-0000-000000000000", 0, VariantNCS, false},
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", 15, VariantFuture, false},
+		{"c232ab00-9414-11ec-b3c8-9f6bdeced846", 1, VariantRFC4122, true},
+		{"5df41881-3aed-3515-88a7-2f4a814cf09e", 3, VariantRFC4122, true},
+		{"6649ea0e-18ca-46f4-8401-bf55fbe14a1b", 4, VariantRFC4122, true},
+		{"6649ea0e-18ca-46f4-c401-bf55fbe14a1b", 4, VariantMicrosoft, false},
+		{"6649ea0e-18ca-96f4-8401-bf55fbe14a1b", 9, VariantRFC4122, false},
+	}
+	for i, test := range tests {
+		uuid := MustParseUUID(test.s)
+		if v := uuid.Version(); v != test.version {
+			t.Errorf("%d: version, got %d, expected %d", i, v, test.version)
+		}
+		if v := uuid.Variant(); v != test.variant {
+			t.Errorf("%d: variant, got %d, expected %d", i, v, test.variant)
+		}
+		if v := uuid.IsValid(); v != test.valid {
+			t.Errorf("%d: valid, got %v, expected %v", i, v, test.valid)
+		}
+	}
+}
+
+func TestUUIDTime(t *testing.T) {
+	// RFC 9562 appendix A: 2022-02-22 19:22:22 UTC.
+	expected := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	for i, s := range []string{
+		"c232ab00-9414-11ec-b3c8-9f6bdeced846",
+		"1ec9414c-232a-6b00-b3c8-9f6bdeced846",
+		"017f22e2-79b0-7cc3-98c4-dc0c0c07398f",
+	} {
+		switch ts, err := MustParseUUID(s).Time(); {
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case !ts.Equal(expected):
+			t.Errorf("%d: got %v, expected %v", i, ts, expected)
+		}
+	}
+	for i, s := range []string{
+		"6649ea0e-18ca-46f4-8401-bf55fbe14a1b",
+		"c232ab00-9414-11ec-c3c8-9f6bdeced846",
+	} {
+		if ts, err := MustParseUUID(s).Time(); err == nil {
+			t.Errorf("%d: expected error, got %v", i, ts)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// ToUUIDv7 embeds a Snowflake ID generated with the layout into a version 7 UUID: the
+// timestamp becomes the UUID's Unix Millisecond timestamp and the node and sequence fill
+// the least significant bits, so UUIDs sort like the IDs. The options must match the
+// ones used for generation. Timestamps must be whole Milliseconds after the Unix epoch,
+// so layouts with a finer Unit are not supported.
+func (l Layout) ToUUIDv7(id int64, opts ...Option) (UUID, error) {
+	var uuid UUID
+	ts, _, _ := l.Decompose(id, opts...)
+	ns := ts.UnixNano()
+	if ns < 0 || ns%int64(time.Millisecond) != 0 {
+		return uuid, fmt.Errorf("Layout.ToUUIDv7(%d): timestamp %v is not in whole Milliseconds after 1970",
+			id, ts)
+	}
+	binary.BigEndian.PutUint64(uuid[8:], uint64(id)&(1<<(l.NodeBits+l.SeqBits)-1))
+	ms := ns / int64(time.Millisecond)
+	for i := 5; i >= 0; i-- {
+		uuid[i] = byte(ms)
+		ms >>= 8
+	}
+	uuid[8] = uuid[8]&0x3f | 0x80
+	uuid[6] = 7 << 4
+	return uuid, nil
+}
+
+// FromUUIDv7 extracts the Snowflake ID embedded by ToUUIDv7, with the same layout and
+// options. An error is returned for UUIDs that ToUUIDv7 could not have produced, e.g.
+// random UUIDv7s.
+func (l Layout) FromUUIDv7(uuid UUID, opts ...Option) (int64, error) {
+	if uuid.Variant() != VariantRFC4122 || uuid.Version() != 7 {
+		return 0, fmt.Errorf("Layout.FromUUIDv7(%v): not a version 7 UUID", uuid)
+	}
+	low := binary.BigEndian.Uint64(uuid[8:]) & (1<<62 - 1)
+	if uuid[6]&0x0f != 0 || uuid[7] != 0 || low>>(l.NodeBits+l.SeqBits) != 0 {
+		return 0, fmt.Errorf("Layout.FromUUIDv7(%v): random bits set, not an embedded ID", uuid)
+	}
+	var ms int64
+	for _, b := range uuid[:6] {
+		ms = ms<<8 | int64(b)
+	}
+	o := newOptions(opts)
+	since := ms*int64(time.Millisecond) - o.epoch
+	if since < 0 || since%int64(l.unit()) != 0 || since/int64(l.unit())>>l.TimeBits != 0 {
+		return 0, fmt.Errorf("Layout.FromUUIDv7(%v): timestamp does not fit the layout", uuid)
+	}
+	return since/int64(l.unit())<<(l.NodeBits+l.SeqBits) | int64(low), nil
+}
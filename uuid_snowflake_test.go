@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowflakeUUIDv7(t *testing.T) {
+	t.Parallel()
+	epoch := WithEpoch(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	var tests = []struct {
+		layout Layout
+		id     int64
+		opts   []Option
+		uuid   string
+	}{
+		{SnowflakeLayout, 1<<22 | 1023<<12 | 4095, nil, "00000000-0001-7000-8000-0000003fffff"},
+		{SnowflakeLayout, 1000<<22 | 5<<12 | 1, []Option{epoch}, "016f5e66-ebe8-7000-8000-000000005001"},
+		{JSSafeLayout, 2<<21 | 31<<16 | 7, nil, "00000000-07d0-7000-8000-0000001f0007"},
+	}
+	for i, test := range tests {
+		uuid, err := test.layout.ToUUIDv7(test.id, test.opts...)
+		if err != nil || uuid.String() != test.uuid {
+			t.Errorf("TestSnowflakeUUIDv7 %d: got %v (error %v), expected %v", i, uuid, err, test.uuid)
+		}
+		if !uuid.IsValid() || uuid.Version() != 7 {
+			t.Errorf("TestSnowflakeUUIDv7 %d: got invalid %v", i, uuid)
+		}
+		if id, err := test.layout.FromUUIDv7(uuid, test.opts...); err != nil || id != test.id {
+			t.Errorf("TestSnowflakeUUIDv7 %d: got %v (error %v), expected %v", i, id, err, test.id)
+		}
+	}
+
+	micro := Layout{TimeBits: 50, NodeBits: 1, SeqBits: 12, Unit: time.Microsecond}
+	if uuid, err := micro.ToUUIDv7(1001 << 13); err == nil {
+		t.Errorf("TestSnowflakeUUIDv7: got %v, expected error for Microseconds", uuid)
+	}
+	random, _ := NewUUIDv7()
+	for _, uuid := range []UUID{random, MustParseUUID("016f5e66-e3e8-4000-8000-000000005001")} {
+		if id, err := SnowflakeLayout.FromUUIDv7(uuid); err == nil {
+			t.Errorf("TestSnowflakeUUIDv7 %v: got %v, expected error", uuid, id)
+		}
+	}
+	// Before the epoch.
+	if id, err := SnowflakeLayout.FromUUIDv7(MustParseUUID(tests[0].uuid), epoch); err == nil {
+		t.Errorf("TestSnowflakeUUIDv7: got %v, expected error", id)
+	}
+}
@@ -0,0 +1,32 @@
+package idgen
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Scan implements sql.Scanner. Columns can be either binary (16 bytes) or text in
+// canonical format. NULL is scanned as NilUUID.
+func (uuid *UUID) Scan(src interface{}) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*uuid = NilUUID
+	case string:
+		*uuid, err = ParseUUID(v)
+	case []byte:
+		if len(v) == len(uuid) {
+			*uuid, err = UUIDFromBytes(v)
+		} else {
+			*uuid, err = ParseUUID(string(v))
+		}
+	default:
+		err = fmt.Errorf("UUID.Scan: unsupported type %T", src)
+	}
+	return err
+}
+
+// Value implements driver.Valuer, using the canonical format.
+func (uuid UUID) Value() (driver.Value, error) {
+	return uuid.String(), nil
+}
@@ -0,0 +1,47 @@
+package idgen
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var (
+	_ sql.Scanner   = (*UUID)(nil)
+	_ driver.Valuer = UUID{}
+)
+
+func TestUUIDScan(t *testing.T) {
+	tests := []struct {
+		src         interface{}
+		expectError bool
+		expected    UUID
+	}{
+		{nil, false, NilUUID},
+		{"6ba7b811-9dad-11d1-80b4-00c04fd430c8", false, NamespaceURL},
+		{[]byte("6ba7b811-9dad-11d1-80b4-00c04fd430c8"), false, NamespaceURL},
+		{NamespaceURL[:], false, NamespaceURL},
+		{"6ba7b811", true, NilUUID},
+		{NamespaceURL[1:], true, NilUUID},
+		{int64(1), true, NilUUID},
+	}
+	for i, test := range tests {
+		uuid := MaxUUID
+		err := uuid.Scan(test.src)
+		switch {
+		case test.expectError && err == nil:
+			t.Errorf("%d: expected error, got value %v", i, uuid)
+		case !test.expectError && err != nil:
+			t.Errorf("%d: got error %q, expected value %v", i, err, test.expected)
+		case !test.expectError && uuid != test.expected:
+			t.Errorf("%d: got %v, expected %v", i, uuid, test.expected)
+		}
+	}
+}
+
+func TestUUIDValue(t *testing.T) {
+	v, err := NamespaceOID.Value()
+	if expected := "6ba7b812-9dad-11d1-80b4-00c04fd430c8"; err != nil || v != expected {
+		t.Errorf("got %v (error %v), expected %s", v, err, expected)
+	}
+}
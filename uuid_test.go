@@ -1,8 +1,10 @@
 package idgen
 
 import (
+	"bytes"
 	"math/rand"
 	"testing"
+	"time"
 )
 
 func TestUUID(t *testing.T) {
@@ -31,3 +33,220 @@ func TestUUID(t *testing.T) {
 
 	}
 }
+
+func TestUUIDv7(t *testing.T) {
+	random := bytes.Repeat([]byte{0xff}, 10)
+	uuid, err := newUUIDv7(bytes.NewReader(random), 0x017f22e279b0)
+	expected := "017f22e2-79b0-7fff-bfff-ffffffffffff"
+	switch {
+	case err != nil:
+		t.Errorf("got error %q", err)
+	case uuid.String() != expected:
+		t.Errorf("repr, got %s, expected %s", uuid, expected)
+	}
+
+	prev, err := NewUUIDv7()
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	next, err := NewUUIDv7()
+	switch {
+	case err != nil:
+		t.Errorf("got error %q", err)
+	case bytes.Compare(prev[:6], next[:6]) >= 0:
+		t.Errorf("ordering, got %v before %v", prev, next)
+	case next[6]>>4 != 7 || next[8]>>6 != 2:
+		t.Errorf("version/variant, got %v", next)
+	}
+}
+
+func TestUUIDv1(t *testing.T) {
+	c := uuidClock{
+		clockSeq: 0x3fff,
+		node:     [6]byte{0x01, 0x23, 0x45, 0x67, 0x89, 0xab},
+		init:     true,
+	}
+	// 1998-02-04 22:13:53.151182 UTC, from RFC 4122 errata examples.
+	const ts = 0x1d19dad8f2e43b0
+	ids := []string{
+		"8f2e43b0-9dad-11d1-bfff-0123456789ab",
+		"8f2e43b0-9dad-11d1-8000-0123456789ab", // same time: clock sequence wraps
+		"8f2e43b1-9dad-11d1-8000-0123456789ab",
+	}
+	for i, expected := range ids {
+		uuid, err := c.newUUIDv1(ts + uint64(i/2))
+		switch {
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case uuid.String() != expected:
+			t.Errorf("%d: repr, got %s, expected %s", i, uuid, expected)
+		}
+	}
+
+	seen := make(map[UUID]bool)
+	for i := 0; i < 1000; i++ {
+		uuid, err := NewUUIDv1()
+		switch {
+		case err != nil:
+			t.Fatalf("%d: %s", i, err)
+		case seen[uuid]:
+			t.Fatalf("%d: duplicate %v", i, uuid)
+		case uuid[6]>>4 != 1 || uuid[8]>>6 != 2:
+			t.Fatalf("%d: version/variant, got %v", i, uuid)
+		}
+		seen[uuid] = true
+	}
+}
+
+func TestUUIDv3v5(t *testing.T) {
+	ids := []struct {
+		v UUID
+		s string
+	}{
+		{NewUUIDv3(NamespaceDNS, []byte("www.example.com")), "5df41881-3aed-3515-88a7-2f4a814cf09e"},
+		{NewUUIDv5(NamespaceDNS, []byte("www.example.com")), "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{NewUUIDv5(NamespaceURL, []byte("http://www.example.com/")), "fcde3c85-2270-590f-9e7c-ee003d65e0e2"},
+	}
+	for i, id := range ids {
+		if s := id.v.String(); s != id.s {
+			t.Errorf("%d: repr, got %s, expected %s", i, s, id.s)
+		}
+	}
+}
+
+func TestUUIDv6(t *testing.T) {
+	c := uuidClock{
+		clockSeq: 0x33c8,
+		node:     [6]byte{0x9f, 0x6b, 0xde, 0xce, 0xd8, 0x46},
+		init:     true,
+	}
+	// Example from RFC 9562 appendix A.5, the same values give A.1 for version 1.
+	const ts = 0x1ec9414c232ab00
+	v6, err := c.newUUIDv6(ts)
+	if expected := "1ec9414c-232a-6b00-b3c8-9f6bdeced846"; err != nil || v6.String() != expected {
+		t.Errorf("v6: got %s (error %v), expected %s", v6, err, expected)
+	}
+	c.lastTime = 0
+	v1, err := c.newUUIDv1(ts)
+	if expected := "c232ab00-9414-11ec-b3c8-9f6bdeced846"; err != nil || v1.String() != expected {
+		t.Errorf("v1: got %s (error %v), expected %s", v1, err, expected)
+	}
+
+	prev, err := NewUUIDv6()
+	for i := 0; i < 100 && err == nil; i++ {
+		var next UUID
+		if next, err = NewUUIDv6(); err == nil && bytes.Compare(prev[:8], next[:8]) > 0 {
+			t.Errorf("%d: ordering, got %v before %v", i, prev, next)
+		}
+		prev = next
+	}
+	if err != nil {
+		t.Errorf("got error %q", err)
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	ids := []struct {
+		s string
+		v UUID
+	}{
+		{"00000000-0000-0000-0000-000000000000", NilUUID},
+		{"ffffffff-ffff-ffff-ffff-ffffffffffff", MaxUUID},
+		{"FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF", MaxUUID},
+		{"6ba7b810-9dad-11d1-80b4-00c04fd430c8", NamespaceDNS},
+	}
+	for i, id := range ids {
+		switch uuid, err := ParseUUID(id.s); {
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case uuid != id.v:
+			t.Errorf("%d: got %v, expected %v", i, uuid, id.v)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c80",
+		"6ba7b8109dad-11d1-80b4-00c04fd430c8-",
+		"6ba7b810-9dad-11d1-80b4-00c04fd430cg",
+		"6ba7b810 9dad 11d1 80b4 00c04fd430c8",
+	} {
+		if uuid, err := ParseUUID(s); err == nil {
+			t.Errorf("ParseUUID(%q): expected error, got %v", s, uuid)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("MustParseUUID: expected panic")
+			}
+		}()
+		MustParseUUID("invalid")
+	}()
+}
+
+func TestUUIDFromBytes(t *testing.T) {
+	if uuid, err := UUIDFromBytes(NamespaceURL[:]); err != nil || uuid != NamespaceURL {
+		t.Errorf("got %v (error %v), expected %v", uuid, err, NamespaceURL)
+	}
+	if uuid, err := UUIDFromBytes(NamespaceURL[1:]); err == nil {
+		t.Errorf("expected error, got %v", uuid)
+	}
+}
+
+func TestUUIDv4Reader(t *testing.T) {
+	uuid, err := NewUUIDv4Reader(bytes.NewReader(bytes.Repeat([]byte{0xff}, 16)))
+	if expected := "ffffffff-ffff-4fff-bfff-ffffffffffff"; err != nil || uuid.String() != expected {
+		t.Errorf("got %v (error %v), expected %s", uuid, err, expected)
+	}
+	if uuid, err := NewUUIDv4Reader(bytes.NewReader(make([]byte, 15))); err == nil {
+		t.Errorf("short read: expected error, got %v", uuid)
+	}
+
+	a, err1 := NewUUIDv4Crypto()
+	b, err2 := NewUUIDv4Crypto()
+	switch {
+	case err1 != nil || err2 != nil:
+		t.Errorf("got errors %v, %v", err1, err2)
+	case a == b:
+		t.Errorf("got duplicate %v", a)
+	case a[6]>>4 != 4 || a[8]>>6 != 2:
+		t.Errorf("version/variant, got %v", a)
+	}
+}
+
+func TestNewUUIDs(t *testing.T) {
+	uuids, err := NewUUIDs(rand.New(rand.NewSource(137)), 2)
+	expected := []string{"6649ea0e-18ca-46f4-8401-bf55fbe14a1b", "d15d1107-c68f-4927-8f62-9931b441dc85"}
+	if err != nil || len(uuids) != len(expected) {
+		t.Fatalf("got %v (error %v), expected %v", uuids, err, expected)
+	}
+	for i, uuid := range uuids {
+		if uuid.String() != expected[i] {
+			t.Errorf("%d: got %v, expected %v", i, uuid, expected[i])
+		}
+	}
+	if uuids, err := NewUUIDs(bytes.NewReader(make([]byte, 31)), 2); err == nil {
+		t.Errorf("short read: expected error, got %v", uuids)
+	}
+	if uuids, err := NewUUIDs(rand.New(rand.NewSource(137)), -1); err == nil {
+		t.Errorf("negative count: expected error, got %v", uuids)
+	}
+}
+
+func BenchmarkUUIDv4Reader(b *testing.B) {
+	r := rand.New(rand.NewSource(137))
+	for i := 0; i < b.N; i++ {
+		NewUUIDv4Reader(r)
+	}
+}
+
+func BenchmarkNewUUIDs(b *testing.B) {
+	r := rand.New(rand.NewSource(137))
+	for i := 0; i < b.N; i += 1000 {
+		NewUUIDs(r, 1000)
+	}
+}
@@ -0,0 +1,199 @@
+package idgen
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DrainPolicy decides what WarmPool.Close does with IDs still in the pool.
+type DrainPolicy int
+
+const (
+	// DrainDiscard drops pooled IDs on Close, leaving gaps; WarmPoolConfig.OnDrain
+	// receives them, e.g. to log or recycle them.
+	DrainDiscard DrainPolicy = iota
+	// DrainServe keeps handing out pooled IDs after Close, without refilling, until the
+	// pool is empty.
+	DrainServe
+)
+
+// WarmPoolConfig configures NewWarmPool.
+type WarmPoolConfig struct {
+	// Size is how many IDs the pool holds when full.
+	Size int
+	// LowWater is the depth below which the pool is refilled, Size/2 if zero.
+	LowWater int
+	// Batch is how many IDs each backend call allocates, Size-LowWater if zero.
+	Batch int64
+	// Drain is the policy on Close.
+	Drain DrainPolicy
+	// OnDrain, if not nil, receives the IDs discarded by DrainDiscard.
+	OnDrain func(ids []int64)
+}
+
+// WarmPoolStats is a snapshot of a WarmPool's metrics.
+type WarmPoolStats struct {
+	// Depth is the number of IDs in the pool.
+	Depth int
+	// Refills counts successful backend calls, Errors the failed ones.
+	Refills, Errors int64
+	// Misses counts calls that found the pool empty and had to wait for a refill.
+	Misses int64
+}
+
+// WarmPool keeps IDs allocated in batches from a backend (e.g. a database sequence or a
+// remote service) and refills in the background when it runs low, so callers only wait
+// for the backend when it cannot keep up. Batches are allocated with NewIDRange, so
+// generators with non-contiguous batches work too. IDs left in the pool when the process
+// exits are never used. Safe for concurrent use.
+type WarmPool struct {
+	gen Interface
+	cfg WarmPoolConfig
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	ids    []int64
+	stats  WarmPoolStats
+	err    error
+	closed bool
+	refill chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewWarmPool returns a WarmPool filled from gen in the background.
+func NewWarmPool(gen Interface, cfg WarmPoolConfig) (*WarmPool, error) {
+	if cfg.LowWater == 0 {
+		cfg.LowWater = cfg.Size / 2
+	}
+	if cfg.Batch == 0 {
+		cfg.Batch = int64(cfg.Size - cfg.LowWater)
+	}
+	if cfg.Size < 1 || cfg.LowWater < 0 || cfg.LowWater >= cfg.Size || cfg.Batch < 1 {
+		return nil, fmt.Errorf("NewWarmPool(): invalid size %d, low water %d or batch %d",
+			cfg.Size, cfg.LowWater, cfg.Batch)
+	}
+	p := &WarmPool{
+		gen:    gen,
+		cfg:    cfg,
+		ids:    make([]int64, 0, cfg.Size+int(cfg.Batch)),
+		refill: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	p.wg.Add(1)
+	go p.run()
+	p.refill <- struct{}{}
+	return p, nil
+}
+
+func (p *WarmPool) run() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.refill:
+		case <-p.done:
+			return
+		}
+		p.mu.Lock()
+		for len(p.ids) < p.cfg.Size && !p.closed {
+			p.mu.Unlock()
+			r, err := NewIDRange(p.gen, p.cfg.Batch)
+			p.mu.Lock()
+			if err != nil {
+				// Waiting callers get the error; the next call retries.
+				p.err = err
+				p.stats.Errors++
+				break
+			}
+			p.err = nil
+			p.stats.Refills++
+			if !p.closed {
+				for id := range r.All() {
+					p.ids = append(p.ids, id)
+				}
+			}
+		}
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	}
+}
+
+// Next returns a pooled ID, waiting for a refill if the pool is empty.
+func (p *WarmPool) Next() (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	missed := false
+	for len(p.ids) == 0 {
+		if p.closed {
+			return 0, ErrClosed
+		}
+		if missed && p.err != nil {
+			return 0, p.err
+		}
+		if !missed {
+			missed = true
+			p.stats.Misses++
+			p.err = nil
+			p.requestRefill()
+		}
+		p.cond.Wait()
+	}
+	id := p.ids[0]
+	p.ids = p.ids[1:]
+	if len(p.ids) <= p.cfg.LowWater && !p.closed {
+		p.requestRefill()
+	}
+	return id, nil
+}
+
+// requestRefill wakes up the background goroutine.
+func (p *WarmPool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// NewIDs implements Interface. It only accepts n=1.
+func (p *WarmPool) NewIDs(n int64) (int64, error) {
+	if n != 1 {
+		return 0, unsupportedCount(p, n)
+	}
+	return p.Next()
+}
+
+// Stats returns the pool's metrics.
+func (p *WarmPool) Stats() WarmPoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.stats
+	s.Depth = len(p.ids)
+	return s
+}
+
+// Close stops refilling, waiting for a backend call in progress, and applies the drain
+// policy. Next returns ErrClosed once the pool is empty.
+func (p *WarmPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+	close(p.done)
+	p.wg.Wait()
+
+	p.mu.Lock()
+	var drained []int64
+	if p.cfg.Drain == DrainDiscard {
+		drained, p.ids = p.ids, nil
+	}
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	if len(drained) > 0 && p.cfg.OnDrain != nil {
+		p.cfg.OnDrain(drained)
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// waitDepth polls until p holds depth IDs.
+func waitDepth(t *testing.T, p *WarmPool, depth int) {
+	t.Helper()
+	for start := time.Now(); p.Stats().Depth != depth; time.Sleep(time.Millisecond) {
+		if time.Since(start) > 5*time.Second {
+			t.Fatalf("got depth %d, expected %d", p.Stats().Depth, depth)
+		}
+	}
+}
+
+func TestWarmPool(t *testing.T) {
+	t.Parallel()
+	p, err := NewWarmPool(NewSequential(), WarmPoolConfig{Size: 10, LowWater: 4, Batch: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	// 4 batches of 3 fill it past Size.
+	waitDepth(t, p, 12)
+	for i := int64(1); i <= 8; i++ {
+		if v, err := p.Next(); err != nil || v != i {
+			t.Fatalf("TestWarmPool %d: got %v (error %v)", i, v, err)
+		}
+	}
+	waitDepth(t, p, 10)
+	var last int64 = 8
+	for i := 0; i < 100; i++ {
+		v, err := p.NewIDs(1)
+		if err != nil || v != last+1 {
+			t.Fatalf("TestWarmPool: got %v (error %v) after %v", v, err, last)
+		}
+		last = v
+	}
+	if s := p.Stats(); s.Refills < 36 || s.Errors != 0 {
+		t.Errorf("TestWarmPool: got stats %+v", s)
+	}
+	if _, err := p.NewIDs(2); !errors.Is(err, ErrUnsupportedCount) {
+		t.Errorf("TestWarmPool: got error %v, expected %v", err, ErrUnsupportedCount)
+	}
+}
+
+func TestWarmPoolError(t *testing.T) {
+	t.Parallel()
+	p, _ := NewWarmPool(broken{errTest}, WarmPoolConfig{Size: 4})
+	defer p.Close()
+	if v, err := p.Next(); err != errTest {
+		t.Errorf("TestWarmPoolError: got %v (error %v), expected %v", v, err, errTest)
+	}
+	if s := p.Stats(); s.Errors < 1 || s.Misses != 1 || s.Depth != 0 {
+		t.Errorf("TestWarmPoolError: got stats %+v", s)
+	}
+}
+
+func TestWarmPoolDrain(t *testing.T) {
+	t.Parallel()
+	var drained []int64
+	p, _ := NewWarmPool(NewSequential(), WarmPoolConfig{
+		Size:    4,
+		OnDrain: func(ids []int64) { drained = ids },
+	})
+	waitDepth(t, p, 4)
+	p.Close()
+	if len(drained) != 4 || drained[0] != 1 || drained[3] != 4 {
+		t.Errorf("TestWarmPoolDrain: got drained %v", drained)
+	}
+	if v, err := p.Next(); err != ErrClosed {
+		t.Errorf("TestWarmPoolDrain: got %v (error %v), expected %v", v, err, ErrClosed)
+	}
+
+	p, _ = NewWarmPool(NewSequential(), WarmPoolConfig{Size: 4, Drain: DrainServe})
+	waitDepth(t, p, 4)
+	p.Close()
+	for i := int64(1); i <= 4; i++ {
+		if v, err := p.Next(); err != nil || v != i {
+			t.Errorf("TestWarmPoolDrain %d: got %v (error %v)", i, v, err)
+		}
+	}
+	if v, err := p.Next(); err != ErrClosed {
+		t.Errorf("TestWarmPoolDrain: got %v (error %v), expected %v", v, err, ErrClosed)
+	}
+
+	for _, cfg := range []WarmPoolConfig{{}, {Size: 4, LowWater: 4}, {Size: 4, Batch: -1}} {
+		if _, err := NewWarmPool(NewSequential(), cfg); err == nil {
+			t.Errorf("TestWarmPoolDrain %+v: expected error", cfg)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package idgen
+
+import (
+	crand "crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// XID is a globally unique, sortable 12-byte ID in the style of rs/xid and MongoDB
+// ObjectID: 4 bytes of timestamp (Seconds since Unix epoch), 3 bytes of machine ID, 2
+// bytes of process ID and a 3-byte counter, big-endian.
+type XID [12]byte
+
+// xidEncoding is lowercase base32hex without padding, so the string form sorts like
+// the binary.
+var xidEncoding = base32.NewEncoding("0123456789abcdefghijklmnopqrstuv").WithPadding(base32.NoPadding)
+
+// XIDGenerator produces XIDs for a machine. Up to 2^24 IDs per second can be generated
+// without clashes. Safe for concurrent use.
+type XIDGenerator struct {
+	machine [3]byte
+	pid     uint16
+	counter uint32
+}
+
+// NewXIDGenerator returns an XID generator for the given machine ID (24 bits). The
+// counter starts from a random value.
+func NewXIDGenerator(machineID int64) (*XIDGenerator, error) {
+	if machineID < 0 || machineID >= 1<<24 {
+		return nil, fmt.Errorf("NewXIDGenerator(%d): machine ID must fit in 24 bits", machineID)
+	}
+	var b [3]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	return &XIDGenerator{
+		machine: [3]byte{byte(machineID >> 16), byte(machineID >> 8), byte(machineID)},
+		pid:     uint16(os.Getpid()),
+		counter: uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2]),
+	}, nil
+}
+
+// New returns a new XID.
+func (g *XIDGenerator) New() XID {
+	return g.newXID(time.Now().Unix())
+}
+
+func (g *XIDGenerator) newXID(sec int64) XID {
+	var xid XID
+	xid[0], xid[1], xid[2], xid[3] = byte(sec>>24), byte(sec>>16), byte(sec>>8), byte(sec)
+	copy(xid[4:7], g.machine[:])
+	xid[7], xid[8] = byte(g.pid>>8), byte(g.pid)
+	c := atomic.AddUint32(&g.counter, 1)
+	xid[9], xid[10], xid[11] = byte(c>>16), byte(c>>8), byte(c)
+	return xid
+}
+
+// ParseXID decodes the representation produced by XID.String.
+func ParseXID(s string) (XID, error) {
+	var xid XID
+	if len(s) != 20 {
+		return xid, fmt.Errorf("ParseXID(%q): invalid format", s)
+	}
+	b, err := xidEncoding.DecodeString(s)
+	if err != nil {
+		return xid, fmt.Errorf("ParseXID(%q): %v", s, err)
+	}
+	copy(xid[:], b)
+	if xid.String() != s {
+		// The last character carries 4 padding bits, which must be zero.
+		return XID{}, fmt.Errorf("ParseXID(%q): invalid format", s)
+	}
+	return xid, nil
+}
+
+// Time returns the XID timestamp.
+func (xid XID) Time() time.Time {
+	return time.Unix(int64(uint32(xid[0])<<24|uint32(xid[1])<<16|uint32(xid[2])<<8|uint32(xid[3])), 0)
+}
+
+// Machine returns the XID machine ID.
+func (xid XID) Machine() int64 {
+	return int64(xid[4])<<16 | int64(xid[5])<<8 | int64(xid[6])
+}
+
+// Pid returns the XID process ID (truncated to 16 bits).
+func (xid XID) Pid() uint16 {
+	return uint16(xid[7])<<8 | uint16(xid[8])
+}
+
+// Counter returns the XID counter.
+func (xid XID) Counter() int64 {
+	return int64(xid[9])<<16 | int64(xid[10])<<8 | int64(xid[11])
+}
+
+// String returns XID in canonical format (20 base32hex characters).
+func (xid XID) String() string {
+	return xidEncoding.EncodeToString(xid[:])
+}
@@ -0,0 +1,66 @@
+package idgen
+
+import (
+	"testing"
+	"time"
+)
+
+func TestXID(t *testing.T) {
+	ids := []struct {
+		v XID
+		s string
+	}{
+		{XID{}, "00000000000000000000"},
+		{[...]byte{0x4d, 0x88, 0xe1, 0x5b, 0x60, 0xf4, 0x86, 0xe4, 0x28, 0x41, 0x2d, 0xc9},
+			"9m4e2mr0ui3e8a215n4g"},
+	}
+	for i, id := range ids {
+		s := id.v.String()
+		v, err := ParseXID(id.s)
+		switch {
+		case s != id.s:
+			t.Errorf("%d: repr, got %s, expected %s", i, s, id.s)
+		case err != nil:
+			t.Errorf("%d: %s", i, err)
+		case v != id.v:
+			t.Errorf("%d: parse, got %v, expected %v", i, v, id.v)
+		}
+	}
+	xid := ids[1].v
+	switch {
+	case !xid.Time().Equal(time.Unix(1300816219, 0)):
+		t.Errorf("time, got %v", xid.Time())
+	case xid.Machine() != 0x60f486:
+		t.Errorf("machine, got %x", xid.Machine())
+	case xid.Pid() != 0xe428:
+		t.Errorf("pid, got %x", xid.Pid())
+	case xid.Counter() != 0x412dc9:
+		t.Errorf("counter, got %x", xid.Counter())
+	}
+	for _, s := range []string{"", "9m4e2mr0ui3e8a215n4", "9M4E2MR0UI3E8A215N4G", "9m4e2mr0ui3e8a215n4w"} {
+		if v, err := ParseXID(s); err == nil {
+			t.Errorf("ParseXID(%q): expected error, got %v", s, v)
+		}
+	}
+}
+
+func TestXIDGenerator(t *testing.T) {
+	if _, err := NewXIDGenerator(1 << 24); err == nil {
+		t.Errorf("expected error for machine ID overflow")
+	}
+	gen, err := NewXIDGenerator(0xabcdef)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	gen.counter = 1<<24 - 2
+	a, b := gen.newXID(1), gen.newXID(2)
+	switch {
+	case a.Machine() != 0xabcdef || a.Counter() != 1<<24-1:
+		t.Errorf("got %v", a)
+	case b.Counter() != 0 || a.String() >= b.String():
+		t.Errorf("ordering, got %v after %v", b, a)
+	}
+	if xid := gen.New(); time.Since(xid.Time()) > time.Second {
+		t.Errorf("time, got %v", xid.Time())
+	}
+}
@@ -0,0 +1,149 @@
+// Package zknode allocates Snowflake nodeMasks using ZooKeeper ephemeral znodes, like
+// Twitter's original Snowflake deployment.
+package zknode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/carloslenz/idgen"
+	"github.com/go-zookeeper/zk"
+)
+
+// Allocator claims a nodeMask by creating the ephemeral znode prefix/nodeMask. Ephemeral
+// sequential znodes are not used because their numbers grow without bound, while
+// nodeMasks must be reused.
+//
+// The connection reconnects automatically and the claim survives as long as the session
+// does. If the session expires, ZooKeeper deletes the znode and Done is closed; a new
+// claim can then be made with Release and Acquire. Safe for concurrent use.
+type Allocator struct {
+	conn     *zk.Conn
+	prefix   string
+	nodeBits byte
+
+	mu   sync.Mutex
+	path string
+	done chan struct{}
+}
+
+var (
+	_ idgen.NodeAllocator = (*Allocator)(nil)
+	_ io.Closer           = (*Allocator)(nil)
+)
+
+// New connects to the ZooKeeper servers and returns an Allocator for nodeMasks of
+// nodeBits (e.g. idgen.SnowflakeLayout.NodeBits), using znodes under prefix (e.g.
+// "/snowflake/workers"), which is created if needed.
+func New(servers []string, sessionTimeout time.Duration, prefix string, nodeBits byte) (*Allocator, error) {
+	conn, events, err := zk.Connect(servers, sessionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	a := &Allocator{conn: conn, prefix: strings.TrimSuffix(prefix, "/"), nodeBits: nodeBits}
+	go a.watch(events)
+	return a, nil
+}
+
+// watch closes done when the session expires (the channel is closed by conn.Close).
+func (a *Allocator) watch(events <-chan zk.Event) {
+	for ev := range events {
+		if ev.State == zk.StateExpired {
+			a.mu.Lock()
+			a.lose()
+			a.mu.Unlock()
+		}
+	}
+}
+
+// lose marks the claim as lost. The caller must hold mu.
+func (a *Allocator) lose() {
+	if a.done != nil {
+		select {
+		case <-a.done:
+		default:
+			close(a.done)
+		}
+	}
+}
+
+// Acquire implements idgen.NodeAllocator, claiming the lowest free nodeMask.
+func (a *Allocator) Acquire(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.path != "" {
+		return 0, errors.New("zknode: already acquired")
+	}
+	if err := a.createParents(); err != nil {
+		return 0, err
+	}
+	host, _ := os.Hostname()
+	owner := []byte(fmt.Sprintf("%s/%d", host, os.Getpid()))
+	for node := int64(0); node < 1<<a.nodeBits; node++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		path := a.prefix + "/" + strconv.FormatInt(node, 10)
+		_, err := a.conn.Create(path, owner, zk.FlagEphemeral, zk.WorldACL(zk.PermAll))
+		switch err {
+		case nil:
+			a.path, a.done = path, make(chan struct{})
+			return node, nil
+		case zk.ErrNodeExists:
+			continue
+		default:
+			return 0, err
+		}
+	}
+	return 0, idgen.ErrNoFreeNode
+}
+
+func (a *Allocator) createParents() error {
+	path := ""
+	for _, part := range strings.Split(strings.TrimPrefix(a.prefix, "/"), "/") {
+		path += "/" + part
+		_, err := a.conn.Create(path, nil, 0, zk.WorldACL(zk.PermAll))
+		if err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+	}
+	return nil
+}
+
+// Release implements idgen.NodeAllocator, deleting the znode.
+func (a *Allocator) Release(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.path == "" {
+		return nil
+	}
+	err := a.conn.Delete(a.path, -1)
+	if err == zk.ErrNoNode {
+		// Already deleted by session expiry.
+		err = nil
+	}
+	a.path = ""
+	a.lose()
+	return err
+}
+
+// Done implements idgen.NodeAllocator. It returns nil before Acquire.
+func (a *Allocator) Done() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.done
+}
+
+// Close implements io.Closer, closing the ZooKeeper connection, which releases the
+// nodeMask.
+func (a *Allocator) Close() error {
+	a.conn.Close()
+	return nil
+}
@@ -0,0 +1,54 @@
+package zknode
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/carloslenz/idgen"
+)
+
+// TestAllocator requires a ZooKeeper server, e.g. ZK_SERVERS=localhost:2181.
+func TestAllocator(t *testing.T) {
+	servers := os.Getenv("ZK_SERVERS")
+	if servers == "" {
+		t.Skip("ZK_SERVERS not set")
+	}
+	ctx := context.Background()
+	prefix := "/idgen-test/" + strings.Replace(time.Now().Format(time.RFC3339Nano), ":", "", -1)
+	var allocators []*Allocator
+	for i := 0; i < 3; i++ {
+		a, err := New(strings.Split(servers, ","), 5*time.Second, prefix, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer a.Close()
+		allocators = append(allocators, a)
+	}
+	a, b, c := allocators[0], allocators[1], allocators[2]
+
+	nodeA, errA := a.Acquire(ctx)
+	nodeB, errB := b.Acquire(ctx)
+	switch {
+	case errA != nil || errB != nil:
+		t.Fatalf("got errors %v, %v", errA, errB)
+	case nodeA == nodeB:
+		t.Fatalf("got same node %v", nodeA)
+	}
+	if node, err := c.Acquire(ctx); err != idgen.ErrNoFreeNode {
+		t.Errorf("got %v (error %v), expected error %v", node, err, idgen.ErrNoFreeNode)
+	}
+	if err := a.Release(ctx); err != nil {
+		t.Errorf("got error %q", err)
+	}
+	select {
+	case <-a.Done():
+	default:
+		t.Errorf("Done not closed after Release")
+	}
+	if node, err := c.Acquire(ctx); err != nil || node != nodeA {
+		t.Errorf("got %v (error %v), expected %v", node, err, nodeA)
+	}
+}